@@ -0,0 +1,164 @@
+package metrics
+
+import (
+	"math"
+	"sync"
+	"sync/atomic"
+
+	"github.com/AlexsanderHamir/PoolX/v2/pool"
+)
+
+// MemorySink is a pool.MetricsSink that keeps every metric in memory, useful in tests and
+// for processes that read the metrics themselves instead of shipping them somewhere.
+type MemorySink struct {
+	mu         sync.Mutex
+	counters   map[string]*MemoryCounter
+	gauges     map[string]*MemoryGauge
+	histograms map[string]*MemoryHistogram
+}
+
+// NewMemorySink creates an empty in-memory sink.
+func NewMemorySink() *MemorySink {
+	return &MemorySink{
+		counters:   make(map[string]*MemoryCounter),
+		gauges:     make(map[string]*MemoryGauge),
+		histograms: make(map[string]*MemoryHistogram),
+	}
+}
+
+// Counter returns the counter registered under name, creating it if needed.
+func (s *MemorySink) Counter(name string) pool.Counter {
+	return s.counter(name)
+}
+
+// Gauge returns the gauge registered under name, creating it if needed.
+func (s *MemorySink) Gauge(name string) pool.Gauge {
+	return s.gauge(name)
+}
+
+// Histogram returns the histogram registered under name, creating it if needed.
+func (s *MemorySink) Histogram(name string) pool.Histogram {
+	return s.histogram(name)
+}
+
+// CounterValue returns the current value of the counter registered under name, 0 if there is none.
+func (s *MemorySink) CounterValue(name string) uint64 {
+	return s.counter(name).Value()
+}
+
+// GaugeValue returns the current value of the gauge registered under name, 0 if there is none.
+func (s *MemorySink) GaugeValue(name string) float64 {
+	return s.gauge(name).Value()
+}
+
+// HistogramSummary returns a summary of the values observed by the histogram registered under name.
+func (s *MemorySink) HistogramSummary(name string) HistogramSummary {
+	return s.histogram(name).Summary()
+}
+
+func (s *MemorySink) counter(name string) *MemoryCounter {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	c, ok := s.counters[name]
+	if !ok {
+		c = &MemoryCounter{}
+		s.counters[name] = c
+	}
+	return c
+}
+
+func (s *MemorySink) gauge(name string) *MemoryGauge {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	g, ok := s.gauges[name]
+	if !ok {
+		g = &MemoryGauge{}
+		s.gauges[name] = g
+	}
+	return g
+}
+
+func (s *MemorySink) histogram(name string) *MemoryHistogram {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	h, ok := s.histograms[name]
+	if !ok {
+		h = &MemoryHistogram{}
+		s.histograms[name] = h
+	}
+	return h
+}
+
+// MemoryCounter is the counter handle of a MemorySink.
+type MemoryCounter struct {
+	value atomic.Uint64
+}
+
+func (c *MemoryCounter) Add(delta uint64) {
+	c.value.Add(delta)
+}
+
+func (c *MemoryCounter) Value() uint64 {
+	return c.value.Load()
+}
+
+// MemoryGauge is the gauge handle of a MemorySink.
+type MemoryGauge struct {
+	bits atomic.Uint64
+}
+
+func (g *MemoryGauge) Set(value float64) {
+	g.bits.Store(math.Float64bits(value))
+}
+
+func (g *MemoryGauge) Value() float64 {
+	return math.Float64frombits(g.bits.Load())
+}
+
+// HistogramSummary summarizes the values observed by a histogram.
+type HistogramSummary struct {
+	Count uint64
+	Sum   float64
+	Min   float64
+	Max   float64
+}
+
+// Mean returns the average observed value, 0 if nothing was observed.
+func (h HistogramSummary) Mean() float64 {
+	if h.Count == 0 {
+		return 0
+	}
+	return h.Sum / float64(h.Count)
+}
+
+// MemoryHistogram is the histogram handle of a MemorySink, it keeps a summary of the observed values.
+type MemoryHistogram struct {
+	mu      sync.Mutex
+	summary HistogramSummary
+}
+
+func (h *MemoryHistogram) Observe(value float64) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if h.summary.Count == 0 || value < h.summary.Min {
+		h.summary.Min = value
+	}
+
+	if h.summary.Count == 0 || value > h.summary.Max {
+		h.summary.Max = value
+	}
+
+	h.summary.Count++
+	h.summary.Sum += value
+}
+
+func (h *MemoryHistogram) Summary() HistogramSummary {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	return h.summary
+}
@@ -0,0 +1,115 @@
+package metrics
+
+import (
+	"context"
+	"errors"
+	"sync"
+
+	"github.com/AlexsanderHamir/PoolX/v2/pool"
+)
+
+// OTelMeter is the subset of an OpenTelemetry metric.Meter used by OTelSink. It's declared here so the
+// module doesn't depend on the OpenTelemetry API, a metric.Meter is adapted to it in a few lines by
+// forwarding to Int64Counter, Float64Gauge and Float64Histogram.
+type OTelMeter interface {
+	Int64Counter(name string) (OTelInt64Counter, error)
+	Float64Gauge(name string) (OTelFloat64Recorder, error)
+	Float64Histogram(name string) (OTelFloat64Recorder, error)
+}
+
+// OTelInt64Counter is the subset of an OpenTelemetry metric.Int64Counter used by OTelSink.
+type OTelInt64Counter interface {
+	Add(ctx context.Context, incr int64)
+}
+
+// OTelFloat64Recorder is the subset of an OpenTelemetry metric.Float64Gauge or metric.Float64Histogram
+// used by OTelSink.
+type OTelFloat64Recorder interface {
+	Record(ctx context.Context, value float64)
+}
+
+// OTelSink is a pool.MetricsSink that creates OpenTelemetry instruments. Instruments that can't be
+// created are replaced by no-ops, Err reports the errors.
+type OTelSink struct {
+	meter  OTelMeter
+	prefix string
+
+	mu   sync.Mutex
+	errs []error
+}
+
+// NewOTelSink creates a sink creating its instruments from meter. Instrument names are prefixed with
+// prefix followed by a dot, if it's not empty.
+func NewOTelSink(meter OTelMeter, prefix string) *OTelSink {
+	if prefix != "" {
+		prefix += "."
+	}
+
+	return &OTelSink{meter: meter, prefix: prefix}
+}
+
+func (s *OTelSink) Counter(name string) pool.Counter {
+	counter, err := s.meter.Int64Counter(s.prefix + name)
+	if err != nil {
+		s.fail(err)
+		return pool.NopMetricsSink{}.Counter(name)
+	}
+	return otelCounter{counter}
+}
+
+func (s *OTelSink) Gauge(name string) pool.Gauge {
+	gauge, err := s.meter.Float64Gauge(s.prefix + name)
+	if err != nil {
+		s.fail(err)
+		return pool.NopMetricsSink{}.Gauge(name)
+	}
+	return otelGauge{gauge}
+}
+
+func (s *OTelSink) Histogram(name string) pool.Histogram {
+	histogram, err := s.meter.Float64Histogram(s.prefix + name)
+	if err != nil {
+		s.fail(err)
+		return pool.NopMetricsSink{}.Histogram(name)
+	}
+	return otelHistogram{histogram}
+}
+
+// Err returns the errors returned by the meter while creating instruments, nil if there were none.
+func (s *OTelSink) Err() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return errors.Join(s.errs...)
+}
+
+func (s *OTelSink) fail(err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.errs = append(s.errs, err)
+}
+
+type otelCounter struct {
+	counter OTelInt64Counter
+}
+
+func (c otelCounter) Add(delta uint64) {
+	c.counter.Add(context.Background(), int64(delta))
+}
+
+type otelGauge struct {
+	gauge OTelFloat64Recorder
+}
+
+func (g otelGauge) Set(value float64) {
+	g.gauge.Record(context.Background(), value)
+}
+
+type otelHistogram struct {
+	histogram OTelFloat64Recorder
+}
+
+func (h otelHistogram) Observe(value float64) {
+	h.histogram.Record(context.Background(), value)
+}
@@ -0,0 +1,212 @@
+// Package metrics exports the statistics of PoolX pools to monitoring systems.
+package metrics
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/AlexsanderHamir/PoolX/v2/pool"
+)
+
+// Source is a pool whose statistics can be exported, *pool.Pool[T] implements it for every T.
+type Source interface {
+	GetPoolStatsSnapshot() *pool.PoolStatsSnapshot
+}
+
+// contentType is the content type of the Prometheus text exposition format.
+const contentType = "text/plain; version=0.0.4; charset=utf-8"
+
+// metric describes a single exported metric family.
+type metric struct {
+	name  string
+	help  string
+	typ   string
+	value func(s *pool.PoolStatsSnapshot) float64
+}
+
+// metricFamilies are the metrics exported for every pool, in exposition order.
+var metricFamilies = []metric{
+	{"poolx_gets_total", "Objects handed out by the pool.", "counter",
+		func(s *pool.PoolStatsSnapshot) float64 { return float64(s.TotalGets) }},
+	{"poolx_returns_total", "Objects returned to the pool, including handoffs to waiters and discarded returns.", "counter",
+		func(s *pool.PoolStatsSnapshot) float64 { return float64(s.TotalReturns()) }},
+	{"poolx_l1_returns_total", "Objects returned directly to the L1 cache.", "counter",
+		func(s *pool.PoolStatsSnapshot) float64 { return float64(s.FastReturnHit) }},
+	{"poolx_ring_buffer_returns_total", "Objects returned to the ring buffer because the L1 cache was full.", "counter",
+		func(s *pool.PoolStatsSnapshot) float64 { return float64(s.FastReturnMiss) }},
+	{"poolx_grows_total", "Growth events of the ring buffer.", "counter",
+		func(s *pool.PoolStatsSnapshot) float64 { return float64(s.TotalGrowthEvents) }},
+	{"poolx_shrinks_total", "Shrink events of the ring buffer.", "counter",
+		func(s *pool.PoolStatsSnapshot) float64 { return float64(s.TotalShrinkEvents) }},
+	{"poolx_objects_created_total", "Objects created by the pool.", "counter",
+		func(s *pool.PoolStatsSnapshot) float64 { return float64(s.ObjectsCreated) }},
+	{"poolx_objects_destroyed_total", "Objects dropped by the pool.", "counter",
+		func(s *pool.PoolStatsSnapshot) float64 { return float64(s.ObjectsDestroyed) }},
+	{"poolx_capacity", "Current capacity of the ring buffer.", "gauge",
+		func(s *pool.PoolStatsSnapshot) float64 { return float64(s.CurrentCapacity) }},
+	{"poolx_objects_in_use", "Objects handed out and not returned yet.", "gauge",
+		func(s *pool.PoolStatsSnapshot) float64 { return float64(s.ObjectsInUse) }},
+	{"poolx_l1_capacity", "Current capacity of the L1 cache.", "gauge",
+		func(s *pool.PoolStatsSnapshot) float64 { return float64(s.CurrentL1Capacity) }},
+	{"poolx_l1_length", "Idle objects in the L1 cache.", "gauge",
+		func(s *pool.PoolStatsSnapshot) float64 { return float64(s.L1Length) }},
+	{"poolx_ring_buffer_length", "Idle objects in the ring buffer.", "gauge",
+		func(s *pool.PoolStatsSnapshot) float64 { return float64(s.RingBufferLength) }},
+	{"poolx_spill_rate", "Ratio of returns that missed the L1 cache and spilled to the ring buffer.", "gauge",
+		func(s *pool.PoolStatsSnapshot) float64 { return s.L2SpillRate }},
+}
+
+// Exporter renders the statistics of registered pools in the Prometheus text exposition format.
+// Every metric carries a pool label with the name the pool was registered with, pools of a
+// registry also carry their registry labels.
+type Exporter struct {
+	mu         sync.RWMutex
+	pools      map[string]Source
+	registries []*pool.Registry
+}
+
+// NewExporter creates an exporter with no registered pools.
+func NewExporter() *Exporter {
+	return &Exporter{pools: make(map[string]Source)}
+}
+
+// Register adds a pool under the given name. Returns an error if the name is empty or already taken.
+func (e *Exporter) Register(name string, source Source) error {
+	if name == "" {
+		return fmt.Errorf("pool name is empty")
+	}
+
+	if source == nil {
+		return fmt.Errorf("pool %q is nil", name)
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if _, ok := e.pools[name]; ok {
+		return fmt.Errorf("pool %q is already registered", name)
+	}
+
+	e.pools[name] = source
+	return nil
+}
+
+// Unregister removes the pool registered under name, if any.
+func (e *Exporter) Unregister(name string) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	delete(e.pools, name)
+}
+
+// RegisterRegistry exports every pool of the registry, including pools registered after the call.
+// Pools are exported under their registry name with their labels, a pool registered with the exporter
+// under the same name takes precedence.
+func (e *Exporter) RegisterRegistry(r *pool.Registry) error {
+	if r == nil {
+		return fmt.Errorf("registry is nil")
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	for _, registered := range e.registries {
+		if registered == r {
+			return fmt.Errorf("registry is already registered")
+		}
+	}
+
+	e.registries = append(e.registries, r)
+	return nil
+}
+
+// ServeHTTP writes the metrics of every registered pool.
+func (e *Exporter) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", contentType)
+	_ = e.Write(w)
+}
+
+// Write renders the metrics of every registered pool to w, pools are sorted by name.
+func (e *Exporter) Write(w io.Writer) error {
+	pools := e.collect()
+
+	bw := bufio.NewWriter(w)
+	for _, m := range metricFamilies {
+		fmt.Fprintf(bw, "# HELP %s %s\n", m.name, m.help)
+		fmt.Fprintf(bw, "# TYPE %s %s\n", m.name, m.typ)
+
+		for _, p := range pools {
+			fmt.Fprintf(bw, "%s{%s} %v\n", m.name, p.labels, m.value(p.snapshot))
+		}
+	}
+
+	return bw.Flush()
+}
+
+// exportedPool is a snapshot of a pool along with its rendered labels.
+type exportedPool struct {
+	name     string
+	labels   string
+	snapshot *pool.PoolStatsSnapshot
+}
+
+// collect takes a snapshot of every registered pool, sorted by name.
+func (e *Exporter) collect() []exportedPool {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+
+	pools := make([]exportedPool, 0, len(e.pools))
+	seen := make(map[string]bool, len(e.pools))
+	for name, source := range e.pools {
+		pools = append(pools, exportedPool{name: name, labels: formatLabels(name, nil), snapshot: source.GetPoolStatsSnapshot()})
+		seen[name] = true
+	}
+
+	for _, r := range e.registries {
+		for _, registered := range r.Pools() {
+			if seen[registered.Name] {
+				continue
+			}
+			seen[registered.Name] = true
+
+			pools = append(pools, exportedPool{
+				name:     registered.Name,
+				labels:   formatLabels(registered.Name, registered.Labels),
+				snapshot: registered.Pool.GetPoolStatsSnapshot(),
+			})
+		}
+	}
+
+	sort.Slice(pools, func(i, j int) bool { return pools[i].name < pools[j].name })
+	return pools
+}
+
+// formatLabels renders the pool label followed by the other labels sorted by name.
+func formatLabels(name string, labels map[string]string) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "pool=\"%s\"", escapeLabelValue(name))
+
+	keys := make([]string, 0, len(labels))
+	for k := range labels {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	for _, k := range keys {
+		fmt.Fprintf(&b, ",%s=\"%s\"", k, escapeLabelValue(labels[k]))
+	}
+
+	return b.String()
+}
+
+// labelEscaper escapes label values as required by the exposition format.
+var labelEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+func escapeLabelValue(v string) string {
+	return labelEscaper.Replace(v)
+}
@@ -0,0 +1,268 @@
+package metrics
+
+import (
+	"bytes"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/AlexsanderHamir/PoolX/v2/pool"
+)
+
+const (
+	// DefaultStatsdFlushInterval is how often a StatsdSink writes its aggregated metrics by default.
+	DefaultStatsdFlushInterval = time.Second
+
+	// DefaultStatsdMaxPacketSize is the default size of a single write, small enough for a UDP datagram
+	// to go through without fragmentation on common networks.
+	DefaultStatsdMaxPacketSize = 1432
+)
+
+// StatsdOptions configures how a StatsdSink batches its writes.
+type StatsdOptions struct {
+	// FlushInterval is how often the aggregated metrics are written, DefaultStatsdFlushInterval if zero.
+	// A negative interval disables the periodic flush, Flush must then be called.
+	FlushInterval time.Duration
+
+	// MaxPacketSize caps the size of a single write, DefaultStatsdMaxPacketSize if zero.
+	// Lines are never split, a line longer than MaxPacketSize is written on its own.
+	MaxPacketSize int
+}
+
+// StatsdSink is a pool.MetricsSink that sends the metrics as statsd lines to w, typically a UDP
+// connection to a statsd agent. Updates don't write anything: counters are summed and gauges keep their
+// last value in memory until the next flush, and histogram observations are buffered and written once
+// they fill a packet or at the next flush, whichever comes first. Histograms are sent with the "h" type,
+// supported by DogStatsD and most statsd servers. Write errors are ignored, like statsd clients usually do.
+//
+// The metrics of a named pool are tagged with pool:<name>, see pool.PoolScopedSink. Close stops the periodic
+// flush and writes what's left.
+type StatsdSink struct {
+	core   *statsdCore
+	prefix string
+	tags   []string
+}
+
+// statsdCore holds the state shared by a sink and the sinks it scoped to a pool.
+type statsdCore struct {
+	w             io.Writer
+	maxPacketSize int
+
+	mu         sync.Mutex
+	counters   []*statsdCounter
+	gauges     []*statsdGauge
+	histograms []byte // buffered histogram lines
+
+	stop      chan struct{}
+	done      chan struct{}
+	closeOnce sync.Once
+}
+
+// NewStatsdSink creates a sink writing to w with the default options. Metric names are prefixed with
+// prefix followed by a dot, if it's not empty. Tags are appended to every line in the DogStatsD format
+// ("key:value"), if any.
+func NewStatsdSink(w io.Writer, prefix string, tags ...string) *StatsdSink {
+	return NewStatsdSinkWithOptions(w, StatsdOptions{}, prefix, tags...)
+}
+
+// NewStatsdSinkWithOptions is NewStatsdSink with the given batching options.
+func NewStatsdSinkWithOptions(w io.Writer, opts StatsdOptions, prefix string, tags ...string) *StatsdSink {
+	if prefix != "" && !strings.HasSuffix(prefix, ".") {
+		prefix += "."
+	}
+
+	if opts.FlushInterval == 0 {
+		opts.FlushInterval = DefaultStatsdFlushInterval
+	}
+	if opts.MaxPacketSize <= 0 {
+		opts.MaxPacketSize = DefaultStatsdMaxPacketSize
+	}
+
+	core := &statsdCore{
+		w:             w,
+		maxPacketSize: opts.MaxPacketSize,
+		stop:          make(chan struct{}),
+		done:          make(chan struct{}),
+	}
+
+	if opts.FlushInterval > 0 {
+		go core.flushEvery(opts.FlushInterval)
+	} else {
+		close(core.done)
+	}
+
+	return &StatsdSink{core: core, prefix: prefix, tags: tags}
+}
+
+// ForPool returns a sink sharing the writer and the batches of s whose lines are also tagged with pool:<name>.
+func (s *StatsdSink) ForPool(name string) pool.MetricsSink {
+	return &StatsdSink{
+		core:   s.core,
+		prefix: s.prefix,
+		tags:   append(append([]string(nil), s.tags...), "pool:"+name),
+	}
+}
+
+func (s *StatsdSink) Counter(name string) pool.Counter {
+	c := &statsdCounter{line: s.line(name, "c")}
+
+	s.core.mu.Lock()
+	s.core.counters = append(s.core.counters, c)
+	s.core.mu.Unlock()
+
+	return c
+}
+
+func (s *StatsdSink) Gauge(name string) pool.Gauge {
+	g := &statsdGauge{line: s.line(name, "g")}
+
+	s.core.mu.Lock()
+	s.core.gauges = append(s.core.gauges, g)
+	s.core.mu.Unlock()
+
+	return g
+}
+
+func (s *StatsdSink) Histogram(name string) pool.Histogram {
+	return &statsdHistogram{core: s.core, line: s.line(name, "h")}
+}
+
+// Flush writes the aggregated counters and gauges and the buffered histogram lines.
+// Counters that didn't change since the last flush aren't written, nor are gauges that weren't set.
+func (s *StatsdSink) Flush() {
+	s.core.flush()
+}
+
+// Close stops the periodic flush and flushes what's left. The sink must not be updated afterwards.
+func (s *StatsdSink) Close() error {
+	s.core.closeOnce.Do(func() {
+		close(s.core.stop)
+	})
+	<-s.core.done
+
+	s.core.flush()
+	return nil
+}
+
+// line returns the constant parts of the lines of the metric name of type typ.
+func (s *StatsdSink) line(name, typ string) statsdLine {
+	tail := "|" + typ
+	if len(s.tags) > 0 {
+		tail += "|#" + strings.Join(s.tags, ",")
+	}
+	return statsdLine{head: s.prefix + name + ":", tail: tail + "\n"}
+}
+
+// statsdLine holds the parts of a "name:value|type|#tags" line around the value.
+type statsdLine struct {
+	head string
+	tail string
+}
+
+// appendTo appends the line with the given value to b.
+func (l statsdLine) appendTo(b []byte, value string) []byte {
+	b = append(b, l.head...)
+	b = append(b, value...)
+	return append(b, l.tail...)
+}
+
+// flushEvery flushes the sink every interval until it's closed.
+func (c *statsdCore) flushEvery(interval time.Duration) {
+	defer close(c.done)
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-c.stop:
+			return
+		case <-ticker.C:
+			c.flush()
+		}
+	}
+}
+
+// flush writes the aggregated metrics and the buffered histogram lines, in packets of at most maxPacketSize bytes.
+func (c *statsdCore) flush() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	lines := c.histograms
+	c.histograms = nil
+
+	for _, counter := range c.counters {
+		if delta := counter.value.Swap(0); delta > 0 {
+			lines = counter.line.appendTo(lines, strconv.FormatUint(delta, 10))
+		}
+	}
+
+	for _, gauge := range c.gauges {
+		if gauge.set.Swap(false) {
+			value := math.Float64frombits(gauge.bits.Load())
+			lines = gauge.line.appendTo(lines, strconv.FormatFloat(value, 'f', -1, 64))
+		}
+	}
+
+	c.write(lines)
+}
+
+// write sends lines in packets of at most maxPacketSize bytes, the caller must hold mu.
+func (c *statsdCore) write(lines []byte) {
+	for len(lines) > 0 {
+		n := len(lines)
+		if n > c.maxPacketSize {
+			// cut after the last line that fits, or after the first line if none does
+			n = bytes.LastIndexByte(lines[:c.maxPacketSize], '\n') + 1
+			if n == 0 {
+				n = bytes.IndexByte(lines, '\n') + 1
+			}
+		}
+
+		_, _ = c.w.Write(lines[:n])
+		lines = lines[n:]
+	}
+}
+
+type statsdCounter struct {
+	line  statsdLine
+	value atomic.Uint64
+}
+
+func (c *statsdCounter) Add(delta uint64) {
+	c.value.Add(delta)
+}
+
+type statsdGauge struct {
+	line statsdLine
+	bits atomic.Uint64
+	set  atomic.Bool
+}
+
+func (g *statsdGauge) Set(value float64) {
+	g.bits.Store(math.Float64bits(value))
+	g.set.Store(true)
+}
+
+type statsdHistogram struct {
+	core *statsdCore
+	line statsdLine
+}
+
+// Observe buffers the observation, the buffer is written once it fills a packet.
+func (h *statsdHistogram) Observe(value float64) {
+	c := h.core
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.histograms = h.line.appendTo(c.histograms, strconv.FormatFloat(value, 'f', -1, 64))
+	if len(c.histograms) >= c.maxPacketSize {
+		c.write(c.histograms)
+		c.histograms = c.histograms[:0]
+	}
+}
@@ -0,0 +1,176 @@
+package test
+
+import (
+	"io"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/AlexsanderHamir/PoolX/v2/metrics"
+	"github.com/AlexsanderHamir/PoolX/v2/pool"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type testObject struct {
+	value int
+}
+
+func newTestPool(t *testing.T) *pool.Pool[*testObject] {
+	t.Helper()
+
+	config, err := pool.NewPoolConfigBuilder[*testObject]().
+		SetPoolBasicConfigs(64, 128, true).
+		Build()
+	require.NoError(t, err)
+
+	obj, err := pool.NewPool(config,
+		func() *testObject { return &testObject{} },
+		func(obj *testObject) { obj.value = 0 },
+		func(obj *testObject) *testObject { dst := *obj; return &dst },
+	)
+	require.NoError(t, err)
+
+	p := obj.(*pool.Pool[*testObject])
+	t.Cleanup(func() { _ = p.Close() })
+
+	return p
+}
+
+func scrape(t *testing.T, exporter *metrics.Exporter) string {
+	t.Helper()
+
+	rec := httptest.NewRecorder()
+	exporter.ServeHTTP(rec, httptest.NewRequest("GET", "/metrics", nil))
+	assert.Equal(t, "text/plain; version=0.0.4; charset=utf-8", rec.Header().Get("Content-Type"))
+
+	body, err := io.ReadAll(rec.Body)
+	require.NoError(t, err)
+	return string(body)
+}
+
+func TestExporterRendersPoolMetrics(t *testing.T) {
+	p := newTestPool(t)
+
+	objs := make([]*testObject, 3)
+	for i := range objs {
+		obj, err := p.Get()
+		require.NoError(t, err)
+		objs[i] = obj
+	}
+	require.NoError(t, p.Put(objs[0]))
+
+	exporter := metrics.NewExporter()
+	require.NoError(t, exporter.Register("buffers", p))
+
+	body := scrape(t, exporter)
+
+	assert.Contains(t, body, "# TYPE poolx_gets_total counter\n")
+	assert.Contains(t, body, "poolx_gets_total{pool=\"buffers\"} 3\n")
+	assert.Contains(t, body, "poolx_returns_total{pool=\"buffers\"} 1\n")
+	assert.Contains(t, body, "# TYPE poolx_capacity gauge\n")
+	assert.Contains(t, body, "poolx_capacity{pool=\"buffers\"} 64\n")
+	assert.Contains(t, body, "poolx_objects_in_use{pool=\"buffers\"} 2\n")
+	assert.Contains(t, body, "poolx_grows_total{pool=\"buffers\"} 0\n")
+	assert.Contains(t, body, "poolx_shrinks_total{pool=\"buffers\"} 0\n")
+	assert.Contains(t, body, "poolx_l1_length{pool=\"buffers\"}")
+	assert.Contains(t, body, "poolx_spill_rate{pool=\"buffers\"}")
+
+	for _, obj := range objs[1:] {
+		require.NoError(t, p.Put(obj))
+	}
+}
+
+func TestExporterReturnsIncludeDiscarded(t *testing.T) {
+	config, err := pool.NewPoolConfigBuilder[*testObject]().
+		SetPoolBasicConfigs(64, 128, true).
+		Build()
+	require.NoError(t, err)
+
+	obj, err := pool.NewPool(config,
+		func() *testObject { return &testObject{} },
+		func(obj *testObject) {
+			if obj.value < 0 {
+				panic("cannot clean")
+			}
+		},
+		func(obj *testObject) *testObject { dst := *obj; return &dst },
+	)
+	require.NoError(t, err)
+
+	p := obj.(*pool.Pool[*testObject])
+	t.Cleanup(func() { _ = p.Close() })
+
+	kept, err := p.Get()
+	require.NoError(t, err)
+	discarded, err := p.Get()
+	require.NoError(t, err)
+
+	discarded.value = -1
+	require.NoError(t, p.Put(kept))
+	require.Error(t, p.Put(discarded))
+
+	exporter := metrics.NewExporter()
+	require.NoError(t, exporter.Register("buffers", p))
+
+	assert.Contains(t, scrape(t, exporter), "poolx_returns_total{pool=\"buffers\"} 2\n")
+}
+
+func TestExporterMultiplePools(t *testing.T) {
+	exporter := metrics.NewExporter()
+	require.NoError(t, exporter.Register("b", newTestPool(t)))
+	require.NoError(t, exporter.Register("a\"quoted\"", newTestPool(t)))
+
+	body := scrape(t, exporter)
+
+	first := strings.Index(body, `poolx_gets_total{pool="a\"quoted\""}`)
+	second := strings.Index(body, `poolx_gets_total{pool="b"}`)
+	require.NotEqual(t, -1, first)
+	require.NotEqual(t, -1, second)
+	assert.Less(t, first, second, "pools should be sorted by name")
+
+	exporter.Unregister("b")
+	assert.NotContains(t, scrape(t, exporter), `pool="b"`)
+}
+
+func TestExporterRegisterErrors(t *testing.T) {
+	exporter := metrics.NewExporter()
+	p := newTestPool(t)
+
+	assert.Error(t, exporter.Register("", p))
+	assert.Error(t, exporter.Register("nil", nil))
+	require.NoError(t, exporter.Register("pool", p))
+	assert.Error(t, exporter.Register("pool", p))
+}
+
+func TestExporterRegistry(t *testing.T) {
+	config, err := pool.NewPoolConfigBuilder[*testObject]().
+		SetPoolBasicConfigs(64, 128, true).
+		SetName("orders").
+		Build()
+	require.NoError(t, err)
+
+	obj, err := pool.NewPool(config,
+		func() *testObject { return &testObject{} },
+		func(obj *testObject) { obj.value = 0 },
+		func(obj *testObject) *testObject { dst := *obj; return &dst },
+	)
+	require.NoError(t, err)
+	orders := obj.(*pool.Pool[*testObject])
+	defer orders.Close()
+
+	registry := pool.NewRegistry()
+	exporter := metrics.NewExporter()
+	require.NoError(t, exporter.RegisterRegistry(registry))
+	assert.Error(t, exporter.RegisterRegistry(registry))
+	assert.Error(t, exporter.RegisterRegistry(nil))
+
+	require.NoError(t, registry.Register(orders, map[string]string{"type": "order", "app": "billing"}))
+
+	body := scrape(t, exporter)
+	assert.Contains(t, body, `poolx_capacity{pool="orders",app="billing",type="order"} 64`)
+
+	registry.Unregister("orders")
+	assert.NotContains(t, scrape(t, exporter), `pool="orders"`)
+}
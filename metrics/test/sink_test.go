@@ -0,0 +1,260 @@
+package test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/AlexsanderHamir/PoolX/v2/metrics"
+	"github.com/AlexsanderHamir/PoolX/v2/pool"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMemorySinkReceivesPoolEvents(t *testing.T) {
+	sink := metrics.NewMemorySink()
+
+	config, err := pool.NewPoolConfigBuilder[*testObject]().
+		SetPoolBasicConfigs(8, 64, true).
+		SetMinShrinkCapacity(1).
+		SetFastPathBasicConfigs(8, 1, 1, 100, 20).
+		SetGrowthExponentialThresholdFactor(4).
+		SetAllocationStrategy(100, 8).
+		SetMetricsSink(sink).
+		Build()
+	require.NoError(t, err)
+
+	obj, err := pool.NewPool(config,
+		func() *testObject { return &testObject{} },
+		func(obj *testObject) { obj.value = 0 },
+		func(obj *testObject) *testObject { dst := *obj; return &dst },
+	)
+	require.NoError(t, err)
+	p := obj.(*pool.Pool[*testObject])
+
+	assert.Equal(t, float64(8), sink.GaugeValue(pool.MetricCapacity))
+	assert.Equal(t, float64(8), sink.GaugeValue(pool.MetricL1Capacity))
+
+	objects := make([]*testObject, 12)
+	for i := range objects {
+		objects[i], err = p.Get()
+		require.NoError(t, err)
+	}
+
+	stats := p.GetPoolStatsSnapshot()
+	gets := sink.CounterValue(pool.MetricGetsL1) + sink.CounterValue(pool.MetricGetsRingBuffer)
+	assert.Equal(t, stats.TotalGets, gets)
+	assert.Positive(t, sink.CounterValue(pool.MetricGetsL1))
+	assert.Equal(t, uint64(stats.ObjectsCreated), sink.CounterValue(pool.MetricAllocations))
+	assert.Equal(t, uint64(stats.TotalGrowthEvents), sink.CounterValue(pool.MetricGrowthEvents))
+	assert.Positive(t, sink.CounterValue(pool.MetricGrowthEvents))
+	assert.Equal(t, float64(stats.CurrentCapacity), sink.GaugeValue(pool.MetricCapacity))
+
+	for _, obj := range objects {
+		require.NoError(t, p.Put(obj))
+	}
+	require.NoError(t, p.Close())
+}
+
+func TestMemoryHistogramSummary(t *testing.T) {
+	sink := metrics.NewMemorySink()
+	h := sink.Histogram(pool.MetricWaitDuration)
+
+	for _, v := range []float64{0.5, 0.1, 0.3} {
+		h.Observe(v)
+	}
+
+	summary := sink.HistogramSummary(pool.MetricWaitDuration)
+	assert.Equal(t, uint64(3), summary.Count)
+	assert.InDelta(t, 0.9, summary.Sum, 1e-9)
+	assert.Equal(t, 0.1, summary.Min)
+	assert.Equal(t, 0.5, summary.Max)
+	assert.InDelta(t, 0.3, summary.Mean(), 1e-9)
+}
+
+// packetWriter records every write as a separate packet.
+type packetWriter struct {
+	mu      sync.Mutex
+	packets []string
+}
+
+func (w *packetWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.packets = append(w.packets, string(p))
+	return len(p), nil
+}
+
+func (w *packetWriter) String() string {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	return strings.Join(w.packets, "")
+}
+
+func TestStatsdSinkLineFormat(t *testing.T) {
+	var buf bytes.Buffer
+	sink := metrics.NewStatsdSinkWithOptions(&buf, metrics.StatsdOptions{FlushInterval: -1}, "poolx", "env:test")
+
+	sink.Histogram(pool.MetricWaitDuration).Observe(0.25)
+	sink.Counter(pool.MetricGetsL1).Add(3)
+	sink.Gauge(pool.MetricCapacity).Set(64)
+	require.NoError(t, sink.Close())
+
+	assert.Equal(t,
+		"poolx.wait_duration:0.25|h|#env:test\n"+
+			"poolx.gets.l1:3|c|#env:test\n"+
+			"poolx.capacity:64|g|#env:test\n",
+		buf.String())
+}
+
+func TestStatsdSinkAggregatesUntilFlush(t *testing.T) {
+	w := &packetWriter{}
+	sink := metrics.NewStatsdSinkWithOptions(w, metrics.StatsdOptions{FlushInterval: -1}, "poolx")
+	defer sink.Close()
+
+	counter := sink.Counter(pool.MetricGetsL1)
+	gauge := sink.Gauge(pool.MetricCapacity)
+	for i := range 1000 {
+		counter.Add(1)
+		gauge.Set(float64(i))
+	}
+	assert.Empty(t, w.String(), "updates don't write anything")
+
+	sink.Flush()
+	assert.Equal(t, "poolx.gets.l1:1000|c\npoolx.capacity:999|g\n", w.String())
+
+	// unchanged metrics aren't sent again
+	sink.Flush()
+	assert.Len(t, w.packets, 1)
+}
+
+func TestStatsdSinkBatchesPackets(t *testing.T) {
+	const maxPacketSize = 64
+
+	w := &packetWriter{}
+	sink := metrics.NewStatsdSinkWithOptions(w, metrics.StatsdOptions{FlushInterval: -1, MaxPacketSize: maxPacketSize}, "")
+	defer sink.Close()
+
+	histogram := sink.Histogram(pool.MetricWaitDuration)
+	for range 10 {
+		histogram.Observe(0.5)
+	}
+
+	// observations are written once they fill a packet, without waiting for a flush
+	require.NotEmpty(t, w.String())
+	sink.Flush()
+
+	assert.Equal(t, strings.Repeat("wait_duration:0.5|h\n", 10), w.String())
+	for _, packet := range w.packets {
+		assert.LessOrEqual(t, len(packet), maxPacketSize)
+		assert.True(t, strings.HasSuffix(packet, "\n"), "lines aren't split across packets")
+	}
+}
+
+func TestStatsdSinkFlushesPeriodically(t *testing.T) {
+	w := &packetWriter{}
+	sink := metrics.NewStatsdSinkWithOptions(w, metrics.StatsdOptions{FlushInterval: time.Millisecond}, "poolx")
+	defer sink.Close()
+
+	sink.Counter(pool.MetricAllocations).Add(2)
+	assert.Eventually(t, func() bool {
+		return w.String() == "poolx.allocations:2|c\n"
+	}, time.Second, time.Millisecond)
+}
+
+func TestMetricsOfNamedPoolsSharingASink(t *testing.T) {
+	memory := metrics.NewMemorySink()
+	w := &packetWriter{}
+	statsd := metrics.NewStatsdSinkWithOptions(w, metrics.StatsdOptions{FlushInterval: -1}, "poolx")
+
+	for _, name := range []string{"orders", "users"} {
+		for _, sink := range []pool.MetricsSink{memory, statsd} {
+			config, err := pool.NewPoolConfigBuilder[*testObject]().
+				SetName(name).
+				SetMetricsSink(sink).
+				Build()
+			require.NoError(t, err)
+
+			obj, err := pool.NewPool(config,
+				func() *testObject { return &testObject{} },
+				func(obj *testObject) {},
+				nil,
+			)
+			require.NoError(t, err)
+			p := obj.(*pool.Pool[*testObject])
+
+			got, err := p.Get()
+			require.NoError(t, err)
+			require.NoError(t, p.Put(got))
+			require.NoError(t, p.Close())
+		}
+	}
+	require.NoError(t, statsd.Close())
+
+	// sinks that don't scope the metrics themselves get the pool name as a prefix
+	assert.Equal(t, uint64(1), memory.CounterValue("orders."+pool.MetricGetsL1))
+	assert.Equal(t, uint64(1), memory.CounterValue("users."+pool.MetricGetsL1))
+	assert.Zero(t, memory.CounterValue(pool.MetricGetsL1))
+
+	// the statsd sink tags them
+	assert.Contains(t, w.String(), "poolx.gets.l1:1|c|#pool:orders\n")
+	assert.Contains(t, w.String(), "poolx.gets.l1:1|c|#pool:users\n")
+}
+
+type fakeInstrument struct {
+	total float64
+}
+
+func (f *fakeInstrument) Add(ctx context.Context, incr int64)       { f.total += float64(incr) }
+func (f *fakeInstrument) Record(ctx context.Context, value float64) { f.total = value }
+
+type fakeMeter struct {
+	instruments map[string]*fakeInstrument
+	failGauges  bool
+}
+
+func (m *fakeMeter) instrument(name string) *fakeInstrument {
+	f, ok := m.instruments[name]
+	if !ok {
+		f = &fakeInstrument{}
+		m.instruments[name] = f
+	}
+	return f
+}
+
+func (m *fakeMeter) Int64Counter(name string) (metrics.OTelInt64Counter, error) {
+	return m.instrument(name), nil
+}
+
+func (m *fakeMeter) Float64Gauge(name string) (metrics.OTelFloat64Recorder, error) {
+	if m.failGauges {
+		return nil, errors.New("gauges are not supported")
+	}
+	return m.instrument(name), nil
+}
+
+func (m *fakeMeter) Float64Histogram(name string) (metrics.OTelFloat64Recorder, error) {
+	return m.instrument(name), nil
+}
+
+func TestOTelSink(t *testing.T) {
+	meter := &fakeMeter{instruments: make(map[string]*fakeInstrument), failGauges: true}
+	sink := metrics.NewOTelSink(meter, "poolx")
+
+	sink.Counter(pool.MetricGetsL1).Add(2)
+	sink.Counter(pool.MetricGetsL1).Add(3)
+	sink.Histogram(pool.MetricWaitDuration).Observe(0.5)
+	sink.Gauge(pool.MetricCapacity).Set(8)
+
+	assert.Equal(t, float64(5), meter.instruments["poolx.gets.l1"].total)
+	assert.Equal(t, 0.5, meter.instruments["poolx.wait_duration"].total)
+	assert.NotContains(t, meter.instruments, "poolx.capacity")
+	assert.Error(t, sink.Err())
+}
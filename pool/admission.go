@@ -0,0 +1,82 @@
+package pool
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/AlexsanderHamir/ringbuffer"
+)
+
+// enterWait admits the caller into a wait for an object, returns false if maxWaiters
+// callers are already waiting. Admitted callers must call leaveWait with the returned
+// start time once the wait is over.
+func (p *Pool[T]) enterWait() (start time.Time, admitted bool) {
+	if !p.admit() {
+		return start, false
+	}
+
+	p.listener.OnWaitStart()
+	return time.Now(), true
+}
+
+// admit takes a waiting slot, returns false if all maxWaiters slots are taken.
+func (p *Pool[T]) admit() bool {
+	maxWaiters := int64(p.config.maxWaiters)
+	if maxWaiters <= 0 {
+		p.waiting.Add(1)
+		return true
+	}
+
+	for {
+		current := p.waiting.Load()
+		if current >= maxWaiters {
+			return false
+		}
+
+		if p.waiting.CompareAndSwap(current, current+1) {
+			return true
+		}
+	}
+}
+
+// leaveWait releases the slot taken by enterWait.
+func (p *Pool[T]) leaveWait(start time.Time) {
+	p.waiting.Add(-1)
+	waited := time.Since(start)
+	p.listener.OnWaitEnd(waited)
+	p.metrics.observeWait(waited)
+}
+
+// overloaded counts and returns the error for a caller rejected by enterWait.
+func (p *Pool[T]) overloaded() error {
+	p.stats.overloaded.Add(1)
+	return fmt.Errorf("%w: %d callers already waiting", ErrOverloaded, p.config.maxWaiters)
+}
+
+// budgetExpired reports whether a caller waiting since start used up its queue-time budget.
+func (p *Pool[T]) budgetExpired(start time.Time) bool {
+	d := p.config.queueTimeBudget
+	return d > 0 && time.Since(start) >= d
+}
+
+// outOfBudget counts and returns the error for a caller whose queue-time budget expired.
+func (p *Pool[T]) outOfBudget() error {
+	p.stats.overloaded.Add(1)
+	return fmt.Errorf("%w: waited longer than the queue-time budget of %v", ErrOverloaded, p.config.queueTimeBudget)
+}
+
+// boundReads caps the read timeout of rb at the queue-time budget, so that callers blocked in the
+// ring buffer wake up once their budget is used up. It's applied to every ring buffer the pool
+// creates, CopyConfig doesn't carry the read timeout over.
+func (p *Pool[T]) boundReads(rb *ringbuffer.RingBuffer[T]) {
+	budget := p.config.queueTimeBudget
+	if budget <= 0 || !p.config.ringBufferConfig.Block {
+		return
+	}
+
+	if readTimeout := p.config.ringBufferConfig.RTimeout; readTimeout > 0 && readTimeout < budget {
+		budget = readTimeout
+	}
+
+	rb.WithReadTimeout(budget)
+}
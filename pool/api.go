@@ -1,6 +1,9 @@
 package pool
 
-import "time"
+import (
+	"log/slog"
+	"time"
+)
 
 // PoolObj represents a generic object pool interface that manages a collection of reusable objects.
 // Type parameter T represents the type of objects stored in the pool.
@@ -111,6 +114,16 @@ type PoolConfigBuilder[T any] interface {
 	//   - minCapacity: Minimum capacity after shrinking
 	SetFastPathShrinkConfigs(shrinkPercent, minCapacity int) PoolConfigBuilder[T]
 
+	// SetFastPathBackgroundReplenisher enables a background goroutine that keeps the L1 cache stocked.
+	// Parameters:
+	//   - enable: Whether the background replenisher should run
+	//   - interval: How often the replenisher checks the L1 occupancy when no Get wakes it up
+	//
+	// The replenisher refills or allocates objects whenever the L1 occupancy drops below refillPercent,
+	// so callers on the L1 hit path never pay the refill latency.
+	// Note: Zero or negative intervals are ignored, the default interval will be used instead.
+	SetFastPathBackgroundReplenisher(enable bool, interval time.Duration) PoolConfigBuilder[T]
+
 	// SetFastPathShrinkAggressiveness configures the fast path shrink behavior using predefined levels.
 	// Uses the same aggressiveness levels as the main pool (1-5).
 	// Panics if:
@@ -124,6 +137,36 @@ type PoolConfigBuilder[T any] interface {
 	//   - allocAmount: Amount of objects to create per request when L1 is empty
 	SetAllocationStrategy(allocPercent int, allocAmount int) PoolConfigBuilder[T]
 
+	// SetCircuitBreaker configures the circuit breaker around the allocator.
+	// Parameters:
+	//   - failureThreshold: Consecutive allocation failures (panics or nil objects) that open the circuit
+	//   - openDuration: How long the circuit stays open before a single probe allocation is attempted
+	//
+	// While the circuit is open the pool doesn't call the allocator, Get fails fast with ErrCircuitOpen
+	// when there are no idle objects left, instead of waiting for a refill or growth that can't happen.
+	// Note: Zero or negative values are ignored, the circuit breaker is disabled unless a threshold is set.
+	SetCircuitBreaker(failureThreshold int, openDuration time.Duration) PoolConfigBuilder[T]
+
+	// SetStatsHistory enables a background sampler that records a snapshot of the statistics every interval,
+	// keeping the last size snapshots. StatsHistory returns them and StatsWindow derives rates (gets/sec,
+	// allocations/sec, growth events/min) and the in-use min, max and percentiles over a window of time.
+	// Note: The history is enabled only when both values are positive.
+	SetStatsHistory(interval time.Duration, size int) PoolConfigBuilder[T]
+
+	// SetSlabAllocation enables or disables slab allocation.
+	// When enabled, objects are allocated in contiguous slabs of allocAmount objects instead of
+	// one allocation per object, which reduces GC scan and allocation overhead during growth bursts.
+	// Slab members start as the zero value of E (T is *E) and are never copied: the allocator and cloneTemplate
+	// aren't called for them, use SetSlabInitializer to set them up in place. A slab stays reachable until
+	// every one of its members has been destroyed.
+	SetSlabAllocation(enable bool) PoolConfigBuilder[T]
+
+	// SetSlabInitializer sets the function initializing each slab member in place, right after its slab is allocated.
+	// It receives a pointer into the slab, so it must not keep values that would be shared between members, and the
+	// member must stay usable at that address (no self-pointers computed elsewhere, no copies of it put back in the pool).
+	// A panic counts as a failed allocation. Only used with slab allocation, members are left zeroed when it's nil.
+	SetSlabInitializer(initialize func(T)) PoolConfigBuilder[T]
+
 	// Single configuration methods
 	// These methods allow fine-grained control over individual parameters.
 	// Default values will be applied to unset parameters.
@@ -184,6 +227,58 @@ type PoolConfigBuilder[T any] interface {
 	SetRingBufferReadTimeout(d time.Duration) PoolConfigBuilder[T]
 	// SetRingBufferWriteTimeout sets the write timeout for the ring buffer
 	SetRingBufferWriteTimeout(d time.Duration) PoolConfigBuilder[T]
+	// SetFairWaiting enables or disables the fair mode, where Get callers blocked on an exhausted pool wait in the
+	// wait queue with priority 0 instead of blocking in the ring buffer, and each returned object is handed directly
+	// to the longest waiter of the highest priority. Get callers arriving while others wait also join the queue
+	// instead of taking an idle object ahead of them. GetPriority and GetAtomic callers wait in the queue regardless.
+	// Fair waiting only applies when the ring buffer is blocking, waits honor the ring buffer timeout.
+	SetFairWaiting(enable bool) PoolConfigBuilder[T]
+	// SetMaxWaiters caps the number of callers waiting for an object: on another goroutine's refill, blocked in the
+	// ring buffer or in the wait queue. Callers beyond the cap fail fast with ErrOverloaded. Zero disables the cap.
+	SetMaxWaiters(count int) PoolConfigBuilder[T]
+	// SetQueueTimeBudget sets how long a caller may wait for an object before failing with ErrOverloaded, wherever
+	// it waits. The read timeout of a blocking ring buffer is capped at the budget. Zero disables the budget.
+	SetQueueTimeBudget(d time.Duration) PoolConfigBuilder[T]
+	// SetPanicHandler sets the function called with every panic recovered from the allocator, cleaner, cloneTemplate or slab initializer.
+	// Panics are always recovered and counted in the stats, objects created by a panicking allocator are never
+	// handed out and objects whose cleaner panicked are discarded. The handler must be safe for concurrent use.
+	SetPanicHandler(handler func(CallbackPanic)) PoolConfigBuilder[T]
+	// SetListener registers a Listener notified of growth, shrink, L1 resizes, allocations, destructions,
+	// blocked growth and waits. Embed NopListener to implement only some of the callbacks.
+	SetListener(listener Listener) PoolConfigBuilder[T]
+	// SetMetricsSink registers a MetricsSink updated directly as events happen: gets per tier, growth, shrink,
+	// allocations, destructions, capacities and wait durations. See the Metric* constants for the metric names, and
+	// PoolScopedSink for how the metrics of named pools sharing a sink are told apart.
+	SetMetricsSink(sink MetricsSink) PoolConfigBuilder[T]
+	// SetGetLatencyHistograms records the latency of every Get and GetPriority call in a histogram per path (L1 hit, own refill,
+	// refill wait, ring buffer, blocked in the ring buffer, wait queue, failed), exposed by GetExtendedStatsSnapshot.
+	// It's disabled by default because it adds two clock reads to the L1 hit path.
+	SetGetLatencyHistograms(enable bool) PoolConfigBuilder[T]
+	// SetProfilerLabels labels goroutines with the pprof labels poolx.pool=<name> and poolx.op=<operation>, so CPU profiles
+	// attribute their samples to the pool: the goroutines the pool runs in the background (shrink, replenish, stats_history)
+	// are labeled with their role. GetPriority and GetAtomic label the whole call with poolx.op=get_priority or get_atomic
+	// on top of the labels of their context, and restore those labels when they return. Get and Put never touch the labels
+	// of the calling goroutine. Trace regions and tasks of the slow operations are recorded regardless.
+	SetProfilerLabels(enable bool) PoolConfigBuilder[T]
+	// SetOutstandingProfile registers a runtime/pprof profile named poolx.outstanding.<name> that records the stack
+	// of every Get whose object wasn't returned with Put yet, so leaked or long-held objects can be inspected with
+	// go tool pprof, like the goroutine or heap profiles. It requires a name, see SetName, and adds a profile update
+	// under a lock to every Get and Put.
+	SetOutstandingProfile(enable bool) PoolConfigBuilder[T]
+	// SetName sets the name identifying the pool, attached to every log record as the "pool" attribute and
+	// prefixed to the errors returned by Get, GetPriority, GetAtomic and Put. A Registry requires it.
+	SetName(name string) PoolConfigBuilder[T]
+	// SetLogger sets the logger receiving the pool's internal diagnostics: resize decisions at info and debug level,
+	// recovered panics and failed background operations at warn and error level. Nothing is logged by default.
+	SetLogger(logger *slog.Logger) PoolConfigBuilder[T]
+	// ApplyEnv overrides the builder values with the environment variables <prefix>_<NAME>_<FIELD>, where NAME is
+	// the pool name set with SetName, upper-cased, with characters other than letters and digits replaced by
+	// underscores, and is left out for unnamed pools. The fields are HARD_LIMIT, INITIAL_CAPACITY,
+	// SHRINK_AGGRESSIVENESS (a level name such as "aggressive" or its number) and RING_BUFFER_TIMEOUT
+	// (a duration such as "500ms", for both reads and writes). Unset and empty variables are ignored.
+	// Call it after the other setters, so the environment wins, and before Build, which validates the result.
+	// Malformed values are reported together, each naming its variable and field, and leave the builder untouched.
+	ApplyEnv(prefix string) (PoolConfigBuilder[T], error)
 	// Build creates and returns a new PoolConfig with the specified settings
 	Build() (*PoolConfig[T], error)
 }
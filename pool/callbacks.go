@@ -0,0 +1,94 @@
+package pool
+
+import (
+	"fmt"
+	"runtime/debug"
+)
+
+// Names of the user callbacks reported in CallbackPanic.
+const (
+	CallbackAllocator       = "allocator"
+	CallbackCleaner         = "cleaner"
+	CallbackCloneTemplate   = "cloneTemplate"
+	CallbackSlabInitializer = "slabInitializer"
+)
+
+// CallbackPanic describes a panic recovered from a user callback.
+type CallbackPanic struct {
+	// Callback is the name of the callback that panicked, one of the Callback constants.
+	Callback string
+
+	// Value is the value passed to panic.
+	Value any
+
+	// Stack is the stack trace of the goroutine at the time of the panic.
+	Stack []byte
+}
+
+func (c CallbackPanic) String() string {
+	return fmt.Sprintf("%s panicked: %v", c.Callback, c.Value)
+}
+
+// recoverCallback counts and reports r, the value recovered from a panic raised by the named callback.
+// Returns the panic event, nil if r is nil because the callback didn't panic.
+func (p *Pool[T]) recoverCallback(callback string, r any) *CallbackPanic {
+	if r == nil {
+		return nil
+	}
+
+	event := &CallbackPanic{
+		Callback: callback,
+		Value:    r,
+		Stack:    debug.Stack(),
+	}
+
+	p.stats.callbackPanics.Add(1)
+	p.reportPanic(*event)
+
+	return event
+}
+
+// reportPanic hands the event to the configured panic handler, a panicking handler is ignored.
+func (p *Pool[T]) reportPanic(event CallbackPanic) {
+	p.logger.Error("recovered panic in callback", "callback", event.Callback, "panic", event.Value)
+
+	handler := p.config.panicHandler
+	if handler == nil {
+		return
+	}
+
+	defer func() {
+		_ = recover()
+	}()
+
+	handler(event)
+}
+
+// callCleaner cleans obj, returns an error if the cleaner panicked.
+func (p *Pool[T]) callCleaner(obj T) (err error) {
+	defer func() {
+		if event := p.recoverCallback(CallbackCleaner, recover()); event != nil {
+			err = fmt.Errorf("%w: %s", errCallbackPanicked, event)
+		}
+	}()
+
+	p.cleaner(obj)
+	return nil
+}
+
+// discard drops an object whose callback panicked, since its state can't be trusted anymore.
+// The object is accounted as returned and destroyed, so the pool can create a replacement.
+func (p *Pool[T]) discard(obj T) {
+	p.stats.discarded.Add(1)
+
+	p.mu.Lock()
+	p.stats.objectsDestroyed++
+	p.mu.Unlock()
+
+	if p.slabs != nil {
+		p.slabs.release(obj)
+	}
+
+	p.listener.OnDestroy(1)
+	p.metrics.destructions.Add(1)
+}
@@ -0,0 +1,209 @@
+package pool
+
+import (
+	"fmt"
+	"reflect"
+	"sync"
+	"time"
+)
+
+// circuitState is the state of the allocation circuit breaker.
+type circuitState int
+
+const (
+	// circuitClosed lets every allocation through.
+	circuitClosed circuitState = iota
+	// circuitOpen rejects allocations until openDuration elapsed.
+	circuitOpen
+	// circuitHalfOpen lets a single probe allocation through to decide whether to close the circuit.
+	circuitHalfOpen
+)
+
+func (s circuitState) String() string {
+	switch s {
+	case circuitClosed:
+		return "closed"
+	case circuitOpen:
+		return "open"
+	case circuitHalfOpen:
+		return "half-open"
+	default:
+		return "unknown"
+	}
+}
+
+// circuitBreaker stops calling the allocator after failureThreshold consecutive failures,
+// for openDuration, after which a single probe allocation decides whether to close it again.
+type circuitBreaker struct {
+	mu sync.Mutex
+
+	failureThreshold int
+	openDuration     time.Duration
+
+	state    circuitState
+	failures int
+	openedAt time.Time
+
+	// probing is true while the half-open probe allocation is in flight.
+	probing bool
+
+	totalFailures int
+	trips         int
+}
+
+// newCircuitBreaker creates a circuit breaker, returns nil if failureThreshold disables it.
+func newCircuitBreaker(failureThreshold int, openDuration time.Duration) *circuitBreaker {
+	if failureThreshold <= 0 {
+		return nil
+	}
+
+	return &circuitBreaker{
+		failureThreshold: failureThreshold,
+		openDuration:     openDuration,
+	}
+}
+
+// allow reports whether an allocation may go through. Once the open period is over,
+// the first caller gets to probe and the circuit stays half-open until the probe is reported.
+func (b *circuitBreaker) allow() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	switch b.state {
+	case circuitOpen:
+		if time.Since(b.openedAt) < b.openDuration {
+			return false
+		}
+		b.state = circuitHalfOpen
+		b.probing = true
+		return true
+	case circuitHalfOpen:
+		if b.probing {
+			return false
+		}
+		b.probing = true
+		return true
+	default:
+		return true
+	}
+}
+
+// isOpen reports whether allocations are currently being rejected, without claiming the probe.
+func (b *circuitBreaker) isOpen() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	switch b.state {
+	case circuitOpen:
+		return time.Since(b.openedAt) < b.openDuration
+	case circuitHalfOpen:
+		return b.probing
+	default:
+		return false
+	}
+}
+
+// success records a successful allocation, closing the circuit.
+func (b *circuitBreaker) success() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.state = circuitClosed
+	b.failures = 0
+	b.probing = false
+}
+
+// failure records a failed allocation, opening the circuit once the threshold is reached
+// or reopening it if the half-open probe failed.
+func (b *circuitBreaker) failure() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.totalFailures++
+	b.failures++
+	b.probing = false
+
+	if b.state == circuitHalfOpen || b.failures >= b.failureThreshold {
+		b.state = circuitOpen
+		b.openedAt = time.Now()
+		b.failures = 0
+		b.trips++
+	}
+}
+
+// snapshot returns the current state, total failures and number of times the circuit opened.
+func (b *circuitBreaker) snapshot() (state circuitState, totalFailures, trips int) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return b.state, b.totalFailures, b.trips
+}
+
+// isCircuitOpen reports whether the allocation circuit breaker currently rejects allocations.
+func (p *Pool[T]) isCircuitOpen() bool {
+	return p.breaker != nil && p.breaker.isOpen()
+}
+
+// errIfCircuitOpen returns ErrCircuitOpen if the circuit is open and there are no idle objects left,
+// so callers fail fast instead of waiting for allocations that won't happen.
+func (p *Pool[T]) errIfCircuitOpen() error {
+	if !p.isCircuitOpen() {
+		return nil
+	}
+
+	p.mu.RLock()
+	idle := len(*p.cacheL1) + p.pool.Length(false)
+	p.mu.RUnlock()
+
+	if idle > 0 {
+		return nil
+	}
+
+	return fmt.Errorf("%w: no idle objects left", ErrCircuitOpen)
+}
+
+// newObject creates a new object through cloneTemplate or the allocator, going through
+// the circuit breaker if it's enabled. A panic or a nil object counts as a failed allocation.
+func (p *Pool[T]) newObject() (obj T, err error) {
+	if p.breaker != nil && !p.breaker.allow() {
+		return obj, ErrCircuitOpen
+	}
+
+	obj, err = p.callAllocator()
+	if p.breaker != nil {
+		if err != nil {
+			p.breaker.failure()
+		} else {
+			p.breaker.success()
+		}
+	}
+
+	return obj, err
+}
+
+// callAllocator calls cloneTemplate or the allocator, turning a panic or a nil object into an error.
+func (p *Pool[T]) callAllocator() (obj T, err error) {
+	callback := CallbackAllocator
+	if p.cloneTemplate != nil {
+		callback = CallbackCloneTemplate
+	}
+
+	defer func() {
+		if event := p.recoverCallback(callback, recover()); event != nil {
+			var zero T
+			obj, err = zero, fmt.Errorf("%w: %w: %s", errAllocationFailed, errCallbackPanicked, event)
+		}
+	}()
+
+	if p.cloneTemplate != nil {
+		obj = p.cloneTemplate(p.template)
+	} else {
+		obj = p.allocator()
+	}
+
+	if v := reflect.ValueOf(obj); !v.IsValid() || v.IsNil() {
+		return obj, fmt.Errorf("%w: %w", errAllocationFailed, errNilObject)
+	}
+
+	return obj, nil
+}
@@ -0,0 +1,151 @@
+package pool
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// envOverrides holds the values read by ApplyEnv, nil for the variables that aren't set.
+type envOverrides struct {
+	hardLimit         *int
+	initialCapacity   *int
+	aggressiveness    *AggressivenessLevel
+	ringBufferTimeout *time.Duration
+}
+
+// ApplyEnv overrides the builder values with the <prefix>_<NAME>_<FIELD> environment variables, see PoolConfigBuilder.
+// With the "POOLX" prefix, the hard limit of a pool named "orders" is read from POOLX_ORDERS_HARD_LIMIT.
+func (b *poolConfigBuilder[T]) ApplyEnv(prefix string) (PoolConfigBuilder[T], error) {
+	env := envPrefix(prefix, b.config.name)
+
+	var overrides envOverrides
+	var errs []error
+
+	if v, ok := lookupEnv(env + "HARD_LIMIT"); ok {
+		n, err := parseEnvInt(env+"HARD_LIMIT", "hardLimit", v)
+		errs = append(errs, err)
+		overrides.hardLimit = &n
+	}
+
+	if v, ok := lookupEnv(env + "INITIAL_CAPACITY"); ok {
+		n, err := parseEnvInt(env+"INITIAL_CAPACITY", "initialCapacity", v)
+		errs = append(errs, err)
+		overrides.initialCapacity = &n
+	}
+
+	if v, ok := lookupEnv(env + "SHRINK_AGGRESSIVENESS"); ok {
+		level, err := parseEnvAggressiveness(env+"SHRINK_AGGRESSIVENESS", v, b.config.shrink.enforceCustomConfig)
+		errs = append(errs, err)
+		overrides.aggressiveness = &level
+	}
+
+	if v, ok := lookupEnv(env + "RING_BUFFER_TIMEOUT"); ok {
+		d, err := parseEnvTimeout(env+"RING_BUFFER_TIMEOUT", v)
+		errs = append(errs, err)
+		overrides.ringBufferTimeout = &d
+	}
+
+	if err := errors.Join(errs...); err != nil {
+		return nil, err
+	}
+
+	if overrides.hardLimit != nil {
+		b.config.hardLimit = *overrides.hardLimit
+	}
+
+	if overrides.initialCapacity != nil {
+		b.config.initialCapacity = *overrides.initialCapacity
+	}
+
+	if overrides.aggressiveness != nil {
+		// the level was checked by parseEnvAggressiveness
+		if _, err := b.SetShrinkAggressiveness(*overrides.aggressiveness); err != nil {
+			return nil, err
+		}
+	}
+
+	if overrides.ringBufferTimeout != nil {
+		b.config.ringBufferConfig.RTimeout = *overrides.ringBufferTimeout
+		b.config.ringBufferConfig.WTimeout = *overrides.ringBufferTimeout
+	}
+
+	return b, nil
+}
+
+// envPrefix returns the prefix of the variables of the pool named name, ending with an underscore.
+func envPrefix(prefix, name string) string {
+	var sb strings.Builder
+	sb.WriteString(prefix)
+	if prefix != "" && !strings.HasSuffix(prefix, "_") {
+		sb.WriteByte('_')
+	}
+
+	if name != "" {
+		for _, r := range strings.ToUpper(name) {
+			if (r >= 'A' && r <= 'Z') || (r >= '0' && r <= '9') {
+				sb.WriteRune(r)
+			} else {
+				sb.WriteByte('_')
+			}
+		}
+		sb.WriteByte('_')
+	}
+
+	return sb.String()
+}
+
+// lookupEnv returns the value of the variable, treating empty values as unset.
+func lookupEnv(name string) (string, bool) {
+	v, ok := os.LookupEnv(name)
+	v = strings.TrimSpace(v)
+	return v, ok && v != ""
+}
+
+func parseEnvInt(variable, field, value string) (int, error) {
+	n, err := strconv.Atoi(value)
+	if err != nil {
+		return 0, fmt.Errorf("%s: invalid %s %q, must be an integer", variable, field, value)
+	}
+	return n, nil
+}
+
+func parseEnvAggressiveness(variable, value string, enforceCustomConfig bool) (AggressivenessLevel, error) {
+	level, err := ParseAggressivenessLevel(strings.ToLower(value))
+	if err != nil {
+		n, convErr := strconv.Atoi(value)
+		if convErr != nil {
+			return 0, fmt.Errorf("%s: invalid aggressivenessLevel %q, must be one of %s or a number between %d and %d",
+				variable, value, strings.Join(aggressivenessNames[AggressivenessConservative:], ", "),
+				AggressivenessConservative, AggressivenessExtreme)
+		}
+		level = AggressivenessLevel(n)
+	}
+
+	if level <= AggressivenessDisabled || level > AggressivenessExtreme {
+		return 0, fmt.Errorf("%s: invalid aggressivenessLevel %q, must be between %d and %d",
+			variable, value, AggressivenessConservative, AggressivenessExtreme)
+	}
+
+	if enforceCustomConfig {
+		return 0, fmt.Errorf("%s: cannot set aggressivenessLevel when EnforceCustomConfig is active", variable)
+	}
+
+	return level, nil
+}
+
+func parseEnvTimeout(variable, value string) (time.Duration, error) {
+	d, err := time.ParseDuration(value)
+	if err != nil {
+		return 0, fmt.Errorf("%s: invalid ringBufferConfig timeout %q, must be a duration such as \"500ms\"", variable, value)
+	}
+
+	if d < 0 {
+		return 0, fmt.Errorf("%s: invalid ringBufferConfig timeout %q, must be >= 0", variable, value)
+	}
+
+	return d, nil
+}
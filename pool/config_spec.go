@@ -0,0 +1,564 @@
+package pool
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/AlexsanderHamir/ringbuffer/config"
+)
+
+// ConfigSpec is an exported, serializable view of a PoolConfig.
+// It holds the effective values of every parameter, defaults included, but not the callbacks
+// (panic handler, listener) which can't be represented outside the process.
+//
+// In JSON, durations are strings such as "1m30s" and the aggressiveness levels are names such as "balanced".
+// Use ParseConfigSpec to decode a configuration file and NewPoolConfigFromSpec to build a validated PoolConfig from it.
+type ConfigSpec struct {
+	Name                       string             `json:"name,omitempty"`
+	InitialCapacity            int                `json:"initial_capacity"`
+	HardLimit                  int                `json:"hard_limit"`
+	Growth                     GrowthSpec         `json:"growth"`
+	Shrink                     ShrinkSpec         `json:"shrink"`
+	FastPath                   FastPathSpec       `json:"fast_path"`
+	RingBuffer                 RingBufferSpec     `json:"ring_buffer"`
+	AllocationStrategy         AllocationStrategy `json:"allocation_strategy"`
+	FairWaiting                bool               `json:"fair_waiting"`
+	MaxWaiters                 int                `json:"max_waiters"`
+	QueueTimeBudget            time.Duration      `json:"queue_time_budget"`
+	CircuitBreakerThreshold    int                `json:"circuit_breaker_threshold"`
+	CircuitBreakerOpenDuration time.Duration      `json:"circuit_breaker_open_duration"`
+	GetLatencyHistograms       bool               `json:"get_latency_histograms"`
+	ProfilerLabels             bool               `json:"profiler_labels"`
+	OutstandingProfile         bool               `json:"outstanding_profile"`
+	StatsHistoryInterval       time.Duration      `json:"stats_history_interval"`
+	StatsHistorySize           int                `json:"stats_history_size"`
+}
+
+// GrowthSpec is the serializable view of the growth parameters.
+type GrowthSpec struct {
+	ThresholdFactor        float64 `json:"threshold_factor"`
+	BigGrowthFactor        float64 `json:"big_growth_factor"`
+	ControlledGrowthFactor float64 `json:"controlled_growth_factor"`
+}
+
+// ShrinkSpec is the serializable view of the shrink parameters.
+type ShrinkSpec struct {
+	EnforceCustomConfig          bool                `json:"enforce_custom_config"`
+	AggressivenessLevel          AggressivenessLevel `json:"aggressiveness_level"`
+	CheckInterval                time.Duration       `json:"check_interval"`
+	ShrinkCooldown               time.Duration       `json:"shrink_cooldown"`
+	MinUtilizationBeforeShrink   int                 `json:"min_utilization_before_shrink"`
+	StableUnderutilizationRounds int                 `json:"stable_underutilization_rounds"`
+	ShrinkPercent                int                 `json:"shrink_percent"`
+	MaxConsecutiveShrinks        int                 `json:"max_consecutive_shrinks"`
+	MinCapacity                  int                 `json:"min_capacity"`
+}
+
+// FastPathSpec is the serializable view of the L1 cache parameters.
+type FastPathSpec struct {
+	InitialSize              int           `json:"initial_size"`
+	GrowthEventsTrigger      int           `json:"growth_events_trigger"`
+	ShrinkEventsTrigger      int           `json:"shrink_events_trigger"`
+	FillAggressiveness       int           `json:"fill_aggressiveness"`
+	RefillPercent            int           `json:"refill_percent"`
+	PreReadBlockHookAttempts int           `json:"pre_read_block_hook_attempts"`
+	EnableChannelGrowth      bool          `json:"enable_channel_growth"`
+	BackgroundReplenish      bool          `json:"background_replenish"`
+	ReplenishInterval        time.Duration `json:"replenish_interval"`
+	Growth                   GrowthSpec    `json:"growth"`
+	Shrink                   ShrinkSpec    `json:"shrink"`
+}
+
+// RingBufferSpec is the serializable view of the ring buffer parameters.
+type RingBufferSpec struct {
+	Block    bool          `json:"block"`
+	RTimeout time.Duration `json:"read_timeout"`
+	WTimeout time.Duration `json:"write_timeout"`
+}
+
+// GetConfigSpec returns the serializable view of the pool configuration.
+func (p *Pool[T]) GetConfigSpec() ConfigSpec {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	return p.config.Spec()
+}
+
+// Spec returns the serializable view of the configuration.
+func (c *PoolConfig[T]) Spec() ConfigSpec {
+	spec := ConfigSpec{
+		Name:                       c.name,
+		InitialCapacity:            c.initialCapacity,
+		HardLimit:                  c.hardLimit,
+		FairWaiting:                c.fairWaiting,
+		MaxWaiters:                 c.maxWaiters,
+		QueueTimeBudget:            c.queueTimeBudget,
+		CircuitBreakerThreshold:    c.circuitBreakerThreshold,
+		CircuitBreakerOpenDuration: c.circuitBreakerOpenDuration,
+		GetLatencyHistograms:       c.getLatencyHistograms,
+		ProfilerLabels:             c.profilerLabels,
+		OutstandingProfile:         c.outstandingProfile,
+		StatsHistoryInterval:       c.statsHistoryInterval,
+		StatsHistorySize:           c.statsHistorySize,
+	}
+
+	if c.growth != nil {
+		spec.Growth = c.growth.spec()
+	}
+
+	if c.shrink != nil {
+		spec.Shrink = c.shrink.spec()
+	}
+
+	if c.fastPath != nil {
+		spec.FastPath = c.fastPath.spec()
+	}
+
+	if c.ringBufferConfig != nil {
+		spec.RingBuffer = RingBufferSpec{
+			Block:    c.ringBufferConfig.Block,
+			RTimeout: c.ringBufferConfig.RTimeout,
+			WTimeout: c.ringBufferConfig.WTimeout,
+		}
+	}
+
+	if c.allocationStrategy != nil {
+		spec.AllocationStrategy = *c.allocationStrategy
+	}
+
+	return spec
+}
+
+func (g *growthParameters) spec() GrowthSpec {
+	return GrowthSpec{
+		ThresholdFactor:        g.thresholdFactor,
+		BigGrowthFactor:        g.bigGrowthFactor,
+		ControlledGrowthFactor: g.controlledGrowthFactor,
+	}
+}
+
+func (s *shrinkParameters) spec() ShrinkSpec {
+	return ShrinkSpec{
+		EnforceCustomConfig:          s.enforceCustomConfig,
+		AggressivenessLevel:          s.aggressivenessLevel,
+		CheckInterval:                s.checkInterval,
+		ShrinkCooldown:               s.shrinkCooldown,
+		MinUtilizationBeforeShrink:   s.minUtilizationBeforeShrink,
+		StableUnderutilizationRounds: s.stableUnderutilizationRounds,
+		ShrinkPercent:                s.shrinkPercent,
+		MaxConsecutiveShrinks:        s.maxConsecutiveShrinks,
+		MinCapacity:                  s.minCapacity,
+	}
+}
+
+func (f *fastPathParameters) spec() FastPathSpec {
+	spec := FastPathSpec{
+		InitialSize:              f.initialSize,
+		GrowthEventsTrigger:      f.growthEventsTrigger,
+		ShrinkEventsTrigger:      f.shrinkEventsTrigger,
+		FillAggressiveness:       f.fillAggressiveness,
+		RefillPercent:            f.refillPercent,
+		PreReadBlockHookAttempts: f.preReadBlockHookAttempts,
+		EnableChannelGrowth:      f.enableChannelGrowth,
+		BackgroundReplenish:      f.backgroundReplenish,
+		ReplenishInterval:        f.replenishInterval,
+	}
+
+	if f.growth != nil {
+		spec.Growth = f.growth.spec()
+	}
+
+	if f.shrink != nil {
+		spec.Shrink = f.shrink.spec()
+	}
+
+	return spec
+}
+
+// aggressivenessNames are the names of the aggressiveness levels in configuration files.
+var aggressivenessNames = [...]string{
+	AggressivenessDisabled:       "disabled",
+	AggressivenessConservative:   "conservative",
+	AggressivenessBalanced:       "balanced",
+	AggressivenessAggressive:     "aggressive",
+	AggressivenessVeryAggressive: "very_aggressive",
+	AggressivenessExtreme:        "extreme",
+}
+
+// String returns the name of the level, such as "balanced".
+func (l AggressivenessLevel) String() string {
+	if l < AggressivenessDisabled || l > AggressivenessExtreme {
+		return fmt.Sprintf("AggressivenessLevel(%d)", int(l))
+	}
+	return aggressivenessNames[l]
+}
+
+// MarshalText encodes the level as its name.
+func (l AggressivenessLevel) MarshalText() ([]byte, error) {
+	if l < AggressivenessDisabled || l > AggressivenessExtreme {
+		return nil, fmt.Errorf("aggressiveness level %d is out of bounds, must be between %d and %d",
+			int(l), AggressivenessDisabled, AggressivenessExtreme)
+	}
+	return []byte(aggressivenessNames[l]), nil
+}
+
+// UnmarshalText decodes a level from its name.
+func (l *AggressivenessLevel) UnmarshalText(text []byte) error {
+	level, err := ParseAggressivenessLevel(string(text))
+	if err != nil {
+		return err
+	}
+
+	*l = level
+	return nil
+}
+
+// ParseAggressivenessLevel returns the level named name, such as "balanced" or "very_aggressive".
+func ParseAggressivenessLevel(name string) (AggressivenessLevel, error) {
+	for level, n := range aggressivenessNames {
+		if n == name {
+			return AggressivenessLevel(level), nil
+		}
+	}
+
+	return 0, fmt.Errorf("unknown aggressiveness level %q, must be one of %s", name, strings.Join(aggressivenessNames[:], ", "))
+}
+
+// jsonDuration is a time.Duration encoded as a string such as "1m30s".
+type jsonDuration time.Duration
+
+func (d jsonDuration) MarshalJSON() ([]byte, error) {
+	return json.Marshal(time.Duration(d).String())
+}
+
+func (d *jsonDuration) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return fmt.Errorf("invalid duration %s, must be a string such as \"1m30s\"", data)
+	}
+
+	parsed, err := time.ParseDuration(s)
+	if err != nil {
+		return fmt.Errorf("invalid duration %q: %w", s, err)
+	}
+
+	*d = jsonDuration(parsed)
+	return nil
+}
+
+// The spec types are encoded through aliases, which don't have the JSON methods, with their durations
+// shadowed by jsonDuration fields. Decoding starts from the current values, so absent fields keep them.
+
+type configSpecJSON ConfigSpec
+
+func (s ConfigSpec) MarshalJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		configSpecJSON
+		QueueTimeBudget            jsonDuration `json:"queue_time_budget"`
+		CircuitBreakerOpenDuration jsonDuration `json:"circuit_breaker_open_duration"`
+		StatsHistoryInterval       jsonDuration `json:"stats_history_interval"`
+	}{
+		configSpecJSON:             configSpecJSON(s),
+		QueueTimeBudget:            jsonDuration(s.QueueTimeBudget),
+		CircuitBreakerOpenDuration: jsonDuration(s.CircuitBreakerOpenDuration),
+		StatsHistoryInterval:       jsonDuration(s.StatsHistoryInterval),
+	})
+}
+
+func (s *ConfigSpec) UnmarshalJSON(data []byte) error {
+	aux := struct {
+		configSpecJSON
+		QueueTimeBudget            jsonDuration `json:"queue_time_budget"`
+		CircuitBreakerOpenDuration jsonDuration `json:"circuit_breaker_open_duration"`
+		StatsHistoryInterval       jsonDuration `json:"stats_history_interval"`
+	}{
+		configSpecJSON:             configSpecJSON(*s),
+		QueueTimeBudget:            jsonDuration(s.QueueTimeBudget),
+		CircuitBreakerOpenDuration: jsonDuration(s.CircuitBreakerOpenDuration),
+		StatsHistoryInterval:       jsonDuration(s.StatsHistoryInterval),
+	}
+
+	if err := json.Unmarshal(data, &aux); err != nil {
+		return err
+	}
+
+	*s = ConfigSpec(aux.configSpecJSON)
+	s.QueueTimeBudget = time.Duration(aux.QueueTimeBudget)
+	s.CircuitBreakerOpenDuration = time.Duration(aux.CircuitBreakerOpenDuration)
+	s.StatsHistoryInterval = time.Duration(aux.StatsHistoryInterval)
+	return nil
+}
+
+type shrinkSpecJSON ShrinkSpec
+
+func (s ShrinkSpec) MarshalJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		shrinkSpecJSON
+		CheckInterval  jsonDuration `json:"check_interval"`
+		ShrinkCooldown jsonDuration `json:"shrink_cooldown"`
+	}{
+		shrinkSpecJSON: shrinkSpecJSON(s),
+		CheckInterval:  jsonDuration(s.CheckInterval),
+		ShrinkCooldown: jsonDuration(s.ShrinkCooldown),
+	})
+}
+
+func (s *ShrinkSpec) UnmarshalJSON(data []byte) error {
+	aux := struct {
+		shrinkSpecJSON
+		CheckInterval  jsonDuration `json:"check_interval"`
+		ShrinkCooldown jsonDuration `json:"shrink_cooldown"`
+	}{
+		shrinkSpecJSON: shrinkSpecJSON(*s),
+		CheckInterval:  jsonDuration(s.CheckInterval),
+		ShrinkCooldown: jsonDuration(s.ShrinkCooldown),
+	}
+
+	if err := json.Unmarshal(data, &aux); err != nil {
+		return err
+	}
+
+	*s = ShrinkSpec(aux.shrinkSpecJSON)
+	s.CheckInterval = time.Duration(aux.CheckInterval)
+	s.ShrinkCooldown = time.Duration(aux.ShrinkCooldown)
+	return nil
+}
+
+type fastPathSpecJSON FastPathSpec
+
+func (s FastPathSpec) MarshalJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		fastPathSpecJSON
+		ReplenishInterval jsonDuration `json:"replenish_interval"`
+	}{
+		fastPathSpecJSON:  fastPathSpecJSON(s),
+		ReplenishInterval: jsonDuration(s.ReplenishInterval),
+	})
+}
+
+func (s *FastPathSpec) UnmarshalJSON(data []byte) error {
+	aux := struct {
+		fastPathSpecJSON
+		ReplenishInterval jsonDuration `json:"replenish_interval"`
+	}{
+		fastPathSpecJSON:  fastPathSpecJSON(*s),
+		ReplenishInterval: jsonDuration(s.ReplenishInterval),
+	}
+
+	if err := json.Unmarshal(data, &aux); err != nil {
+		return err
+	}
+
+	*s = FastPathSpec(aux.fastPathSpecJSON)
+	s.ReplenishInterval = time.Duration(aux.ReplenishInterval)
+	return nil
+}
+
+type ringBufferSpecJSON RingBufferSpec
+
+func (s RingBufferSpec) MarshalJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		ringBufferSpecJSON
+		RTimeout jsonDuration `json:"read_timeout"`
+		WTimeout jsonDuration `json:"write_timeout"`
+	}{
+		ringBufferSpecJSON: ringBufferSpecJSON(s),
+		RTimeout:           jsonDuration(s.RTimeout),
+		WTimeout:           jsonDuration(s.WTimeout),
+	})
+}
+
+func (s *RingBufferSpec) UnmarshalJSON(data []byte) error {
+	aux := struct {
+		ringBufferSpecJSON
+		RTimeout jsonDuration `json:"read_timeout"`
+		WTimeout jsonDuration `json:"write_timeout"`
+	}{
+		ringBufferSpecJSON: ringBufferSpecJSON(*s),
+		RTimeout:           jsonDuration(s.RTimeout),
+		WTimeout:           jsonDuration(s.WTimeout),
+	}
+
+	if err := json.Unmarshal(data, &aux); err != nil {
+		return err
+	}
+
+	*s = RingBufferSpec(aux.ringBufferSpecJSON)
+	s.RTimeout = time.Duration(aux.RTimeout)
+	s.WTimeout = time.Duration(aux.WTimeout)
+	return nil
+}
+
+// ParseConfigSpec decodes a JSON configuration, such as the output of json.Marshal on a ConfigSpec.
+// Parameters missing from data keep their default values: the shrink parameters take the values of
+// shrink.aggressiveness_level, as with SetShrinkAggressiveness, or are zero with shrink.enforce_custom_config,
+// as with EnforceCustomConfig. The result isn't validated, NewPoolConfigFromSpec and Validate do it.
+func ParseConfigSpec(data []byte) (ConfigSpec, error) {
+	defaults := NewPoolConfigBuilder[any]().(*poolConfigBuilder[any]).config.Spec()
+	return decodeConfigSpec(defaults, data)
+}
+
+// decodeConfigSpec decodes data on top of base. When data enforces custom shrink configuration, or changes
+// the shrink aggressiveness level, the shrink parameters are reset first the way EnforceCustomConfig and
+// SetShrinkAggressiveness do, so the parameters missing from data take the values of the new level.
+func decodeConfigSpec(base ConfigSpec, data []byte) (ConfigSpec, error) {
+	var probe struct {
+		Shrink struct {
+			EnforceCustomConfig *bool                `json:"enforce_custom_config"`
+			AggressivenessLevel *AggressivenessLevel `json:"aggressiveness_level"`
+		} `json:"shrink"`
+	}
+	if err := json.Unmarshal(data, &probe); err != nil {
+		return ConfigSpec{}, fmt.Errorf("invalid pool configuration: %w", err)
+	}
+
+	enforce, level := probe.Shrink.EnforceCustomConfig, probe.Shrink.AggressivenessLevel
+	switch {
+	case enforce != nil && *enforce:
+		if level != nil && *level != AggressivenessDisabled {
+			return ConfigSpec{}, fmt.Errorf("invalid pool configuration: cannot set AggressivenessLevel when EnforceCustomConfig is active")
+		}
+
+		if !base.Shrink.EnforceCustomConfig {
+			base.Shrink.EnforceCustomConfig = true
+			base.Shrink.applyLevel(AggressivenessDisabled)
+		}
+	case level != nil && *level != base.Shrink.AggressivenessLevel:
+		if base.Shrink.EnforceCustomConfig && enforce == nil {
+			return ConfigSpec{}, fmt.Errorf("invalid pool configuration: cannot set AggressivenessLevel when EnforceCustomConfig is active")
+		}
+
+		base.Shrink.EnforceCustomConfig = false
+		base.Shrink.applyLevel(*level)
+		base.FastPath.Shrink.applyLevel(*level)
+		base.FastPath.Shrink.MinCapacity = defaultL1MinCapacity
+	}
+
+	if err := json.Unmarshal(data, &base); err != nil {
+		return ConfigSpec{}, fmt.Errorf("invalid pool configuration: %w", err)
+	}
+
+	return base, nil
+}
+
+// applyLevel sets the aggressiveness level and the parameters it defines, like ApplyDefaults.
+func (s *ShrinkSpec) applyLevel(level AggressivenessLevel) {
+	params := s.parameters()
+	params.aggressivenessLevel = level
+	params.ApplyDefaults(getShrinkDefaultsMap())
+	*s = params.spec()
+}
+
+// Validate checks the spec with the same rules as Build.
+func (s ConfigSpec) Validate() error {
+	_, err := NewPoolConfigFromSpec[any](s)
+	return err
+}
+
+// NewPoolConfigFromSpec builds a PoolConfig from spec, validated by the same rules as Build.
+// The callbacks, which a ConfigSpec doesn't hold, are unset.
+func NewPoolConfigFromSpec[T any](spec ConfigSpec) (*PoolConfig[T], error) {
+	builder := NewPoolConfigBuilder[T]().(*poolConfigBuilder[T])
+	builder.applySpec(spec)
+	return builder.Build()
+}
+
+// applySpec overwrites every serializable parameter of the builder configuration with the values of spec.
+func (b *poolConfigBuilder[T]) applySpec(spec ConfigSpec) {
+	c := b.config
+
+	c.name = spec.Name
+	c.initialCapacity = spec.InitialCapacity
+	c.hardLimit = spec.HardLimit
+	c.growth = spec.Growth.parameters()
+	c.shrink = spec.Shrink.parameters()
+	c.fastPath = spec.FastPath.parameters()
+	c.ringBufferConfig = &config.RingBufferConfig[T]{
+		Block:    spec.RingBuffer.Block,
+		RTimeout: spec.RingBuffer.RTimeout,
+		WTimeout: spec.RingBuffer.WTimeout,
+	}
+
+	allocationStrategy := spec.AllocationStrategy
+	c.allocationStrategy = &allocationStrategy
+
+	c.fairWaiting = spec.FairWaiting
+	c.maxWaiters = spec.MaxWaiters
+	c.queueTimeBudget = spec.QueueTimeBudget
+	c.circuitBreakerThreshold = spec.CircuitBreakerThreshold
+	c.circuitBreakerOpenDuration = spec.CircuitBreakerOpenDuration
+	c.getLatencyHistograms = spec.GetLatencyHistograms
+	c.profilerLabels = spec.ProfilerLabels
+	c.outstandingProfile = spec.OutstandingProfile
+	c.statsHistoryInterval = spec.StatsHistoryInterval
+	c.statsHistorySize = spec.StatsHistorySize
+}
+
+func (g GrowthSpec) parameters() *growthParameters {
+	return &growthParameters{
+		thresholdFactor:        g.ThresholdFactor,
+		bigGrowthFactor:        g.BigGrowthFactor,
+		controlledGrowthFactor: g.ControlledGrowthFactor,
+	}
+}
+
+func (s ShrinkSpec) parameters() *shrinkParameters {
+	return &shrinkParameters{
+		enforceCustomConfig:          s.EnforceCustomConfig,
+		aggressivenessLevel:          s.AggressivenessLevel,
+		checkInterval:                s.CheckInterval,
+		shrinkCooldown:               s.ShrinkCooldown,
+		minUtilizationBeforeShrink:   s.MinUtilizationBeforeShrink,
+		stableUnderutilizationRounds: s.StableUnderutilizationRounds,
+		shrinkPercent:                s.ShrinkPercent,
+		maxConsecutiveShrinks:        s.MaxConsecutiveShrinks,
+		minCapacity:                  s.MinCapacity,
+	}
+}
+
+func (f FastPathSpec) parameters() *fastPathParameters {
+	return &fastPathParameters{
+		initialSize:              f.InitialSize,
+		growthEventsTrigger:      f.GrowthEventsTrigger,
+		shrinkEventsTrigger:      f.ShrinkEventsTrigger,
+		fillAggressiveness:       f.FillAggressiveness,
+		refillPercent:            f.RefillPercent,
+		preReadBlockHookAttempts: f.PreReadBlockHookAttempts,
+		growth:                   f.Growth.parameters(),
+		shrink:                   f.Shrink.parameters(),
+		enableChannelGrowth:      f.EnableChannelGrowth,
+		backgroundReplenish:      f.BackgroundReplenish,
+		replenishInterval:        f.ReplenishInterval,
+	}
+}
+
+// MarshalJSON encodes the serializable view of the configuration, see ConfigSpec.
+func (c *PoolConfig[T]) MarshalJSON() ([]byte, error) {
+	return json.Marshal(c.Spec())
+}
+
+// UnmarshalJSON decodes a configuration with ParseConfigSpec and validates it like Build.
+// On success, every serializable parameter is replaced and the callbacks of c are kept.
+// On error, c is left untouched.
+func (c *PoolConfig[T]) UnmarshalJSON(data []byte) error {
+	spec, err := ParseConfigSpec(data)
+	if err != nil {
+		return err
+	}
+
+	decoded, err := NewPoolConfigFromSpec[T](spec)
+	if err != nil {
+		return err
+	}
+
+	decoded.panicHandler = c.panicHandler
+	decoded.listener = c.listener
+	decoded.metricsSink = c.metricsSink
+	decoded.logger = c.logger
+	decoded.slabInitializer = c.slabInitializer
+
+	*c = *decoded
+	return nil
+}
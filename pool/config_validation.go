@@ -8,6 +8,9 @@ import (
 // - initialCapacity must be positive
 // - hardLimit must be positive and greater than initialCapacity
 // - hardLimit must be greater than or equal to minCapacity
+// - maxWaiters, queueTimeBudget and circuitBreakerThreshold must be non-negative
+// - circuitBreakerOpenDuration must be positive when the circuit breaker is enabled
+// - statsHistoryInterval and statsHistorySize must be both positive or both zero
 // Returns an error if any validation fails.
 func (b *poolConfigBuilder[T]) validateBasicConfig() error {
 	if b.config.initialCapacity <= 0 {
@@ -26,6 +29,32 @@ func (b *poolConfigBuilder[T]) validateBasicConfig() error {
 		return fmt.Errorf("hardLimit (%d) must be >= minCapacity (%d)", b.config.hardLimit, b.config.shrink.minCapacity)
 	}
 
+	if b.config.maxWaiters < 0 {
+		return fmt.Errorf("maxWaiters must be >= 0, got %d", b.config.maxWaiters)
+	}
+
+	if b.config.queueTimeBudget < 0 {
+		return fmt.Errorf("queueTimeBudget must be >= 0, got %v", b.config.queueTimeBudget)
+	}
+
+	if b.config.circuitBreakerThreshold < 0 {
+		return fmt.Errorf("circuitBreakerThreshold must be >= 0, got %d", b.config.circuitBreakerThreshold)
+	}
+
+	if b.config.circuitBreakerThreshold > 0 && b.config.circuitBreakerOpenDuration <= 0 {
+		return fmt.Errorf("circuitBreakerOpenDuration must be greater than 0, got %v", b.config.circuitBreakerOpenDuration)
+	}
+
+	if b.config.statsHistoryInterval < 0 || b.config.statsHistorySize < 0 ||
+		(b.config.statsHistoryInterval > 0) != (b.config.statsHistorySize > 0) {
+		return fmt.Errorf("statsHistoryInterval (%v) and statsHistorySize (%d) must be both greater than 0 or both 0",
+			b.config.statsHistoryInterval, b.config.statsHistorySize)
+	}
+
+	if b.config.outstandingProfile && b.config.name == "" {
+		return fmt.Errorf("the outstanding profile requires a pool name, set one with SetName")
+	}
+
 	return nil
 }
 
@@ -39,10 +68,15 @@ func (b *poolConfigBuilder[T]) validateBasicConfig() error {
 // - minUtilizationBeforeShrink must be between 0 and 1.0
 // - stableUnderutilizationRounds must be positive
 // - shrinkPercent must be between 0 and 1.0
+// - aggressivenessLevel must be a known level
 // Returns an error if any validation fails.
 func (b *poolConfigBuilder[T]) validateShrinkConfig() error {
 	sp := b.config.shrink
 
+	if sp.aggressivenessLevel < AggressivenessDisabled || sp.aggressivenessLevel > AggressivenessExtreme {
+		return fmt.Errorf("aggressivenessLevel must be between %d and %d, got %d", AggressivenessDisabled, AggressivenessExtreme, int(sp.aggressivenessLevel))
+	}
+
 	if sp.maxConsecutiveShrinks < 0 {
 		return fmt.Errorf("maxConsecutiveShrinks must be >= 0, got %d", sp.maxConsecutiveShrinks)
 	}
@@ -109,6 +143,7 @@ func (b *poolConfigBuilder[T]) validateGrowthConfig() error {
 // - growth parameters (exponentialThresholdFactor, growthPercent, fixedGrowthFactor) must be positive
 // - shrinkEventsTrigger must be positive
 // - shrink parameters (minCapacity, shrinkPercent) must be positive
+// - replenishInterval must be positive when the background replenisher is enabled
 // Returns an error if any validation fails.
 func (b *poolConfigBuilder[T]) validateFastPathConfig() error {
 	fp := b.config.fastPath
@@ -153,6 +188,10 @@ func (b *poolConfigBuilder[T]) validateFastPathConfig() error {
 		return fmt.Errorf("fastPath.shrink.shrinkPercent must be greater than 0, got %d", fp.shrink.shrinkPercent)
 	}
 
+	if fp.backgroundReplenish && fp.replenishInterval <= 0 {
+		return fmt.Errorf("fastPath.replenishInterval must be greater than 0, got %v", fp.replenishInterval)
+	}
+
 	return nil
 }
 
@@ -0,0 +1,208 @@
+package pool
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/signal"
+	"sort"
+	"sync"
+	"time"
+)
+
+// ConfigWatcher keeps the pools of a Registry in sync with a JSON configuration file, so their sizing can be
+// tuned by changing the file instead of deploying. The file maps pool names to configurations in the
+// ConfigSpec format, holding only the parameters to change:
+//
+//	{
+//		"orders": {"hard_limit": 4096, "shrink": {"aggressiveness_level": "conservative"}},
+//		"sessions": {"shrink": {"check_interval": "30s"}}
+//	}
+//
+// Each configuration is decoded on top of the current configuration of its pool, validated by the same rules
+// as Build and applied with Reconfigure. A file is applied as a whole: when it's malformed, names a pool that
+// isn't registered, or holds a configuration that is invalid or changes a parameter Reconfigure can't change,
+// it's rejected, the error is reported and no pool is changed. Replace the file atomically, by writing another
+// file and renaming it, or a partial write may be read and rejected before the complete file is applied.
+type ConfigWatcher struct {
+	path     string
+	registry *Registry
+	onReload func(reconfigured []string, err error)
+
+	// mu serializes the reloads and protects the result of the last read
+	mu      sync.Mutex
+	read    bool
+	last    []byte
+	lastErr string
+}
+
+// NewConfigWatcher creates a watcher applying the file at path to the pools of registry.
+func NewConfigWatcher(path string, registry *Registry) *ConfigWatcher {
+	return &ConfigWatcher{path: path, registry: registry}
+}
+
+// OnReload sets the function called after every reload with the names of the reconfigured pools,
+// or the error the file was rejected with. It must be set before Watch is called.
+func (w *ConfigWatcher) OnReload(fn func(reconfigured []string, err error)) *ConfigWatcher {
+	w.onReload = fn
+	return w
+}
+
+// Reload reads the file and applies it now. It returns the names of the reconfigured pools, which leaves
+// out the pools whose configuration didn't change, or the error the file was rejected with.
+func (w *ConfigWatcher) Reload() ([]string, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	return w.reload(false)
+}
+
+// Watch applies the file, then reloads it whenever its content changes, checked every interval, or one of
+// signals, such as syscall.SIGHUP, is received, until ctx is done. Polling is disabled when interval is 0.
+// A file that can't be read or is rejected is reported once, until its content changes or a signal is received.
+func (w *ConfigWatcher) Watch(ctx context.Context, interval time.Duration, signals ...os.Signal) {
+	var tick <-chan time.Time
+	if interval > 0 {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		tick = ticker.C
+	}
+
+	var reload chan os.Signal
+	if len(signals) > 0 {
+		reload = make(chan os.Signal, 1)
+		signal.Notify(reload, signals...)
+		defer signal.Stop(reload)
+	}
+
+	_, _ = w.Reload()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-tick:
+			w.mu.Lock()
+			_, _ = w.reload(true)
+			w.mu.Unlock()
+		case <-reload:
+			_, _ = w.Reload()
+		}
+	}
+}
+
+// reload reads and applies the file, reporting the result. With changedOnly, nothing is done when the content
+// of the file, or the error reading it, is the same as on the previous read.
+func (w *ConfigWatcher) reload(changedOnly bool) ([]string, error) {
+	data, err := os.ReadFile(w.path)
+
+	var errMsg string
+	if err != nil {
+		errMsg = err.Error()
+	}
+
+	if changedOnly && w.read && errMsg == w.lastErr && bytes.Equal(data, w.last) {
+		return nil, nil
+	}
+	w.read, w.last, w.lastErr = true, data, errMsg
+
+	var reconfigured []string
+	if err != nil {
+		err = fmt.Errorf("config file %s: %w", w.path, err)
+	} else {
+		reconfigured, err = w.apply(data)
+	}
+
+	if w.onReload != nil {
+		w.onReload(reconfigured, err)
+	}
+
+	return reconfigured, err
+}
+
+// pendingChange is a validated configuration waiting to be applied to a pool.
+type pendingChange struct {
+	name string
+	pool Reconfigurable
+	spec ConfigSpec
+}
+
+// apply validates the configuration of every pool in data before reconfiguring any of them.
+func (w *ConfigWatcher) apply(data []byte) ([]string, error) {
+	var configs map[string]json.RawMessage
+	if err := json.Unmarshal(data, &configs); err != nil {
+		return nil, fmt.Errorf("config file %s rejected: %w", w.path, err)
+	}
+
+	names := make([]string, 0, len(configs))
+	for name := range configs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var changes []pendingChange
+	var errs []error
+	for _, name := range names {
+		change, changed, err := w.prepare(name, configs[name])
+		if err != nil {
+			errs = append(errs, fmt.Errorf("pool %q: %w", name, err))
+			continue
+		}
+
+		if changed {
+			changes = append(changes, change)
+		}
+	}
+
+	if len(errs) > 0 {
+		return nil, fmt.Errorf("config file %s rejected: %w", w.path, errors.Join(errs...))
+	}
+
+	// the pools were checked, Reconfigure only fails for pools closed in the meantime
+	var reconfigured []string
+	for _, change := range changes {
+		if err := change.pool.Reconfigure(change.spec); err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		reconfigured = append(reconfigured, change.name)
+	}
+
+	if len(errs) > 0 {
+		return reconfigured, fmt.Errorf("config file %s partially applied: %w", w.path, errors.Join(errs...))
+	}
+
+	return reconfigured, nil
+}
+
+// prepare decodes the configuration of the pool named name on top of its current configuration and validates it.
+func (w *ConfigWatcher) prepare(name string, data []byte) (change pendingChange, changed bool, err error) {
+	entry, ok := w.registry.Lookup(name)
+	if !ok {
+		return change, false, fmt.Errorf("not registered")
+	}
+
+	target, ok := entry.Pool.(Reconfigurable)
+	if !ok {
+		return change, false, fmt.Errorf("doesn't support reconfiguration")
+	}
+
+	current := target.GetConfigSpec()
+	spec, err := decodeConfigSpec(current, data)
+	if err != nil {
+		return change, false, err
+	}
+
+	if err := checkReconfigurable(current, spec); err != nil {
+		return change, false, err
+	}
+
+	if err := spec.Validate(); err != nil {
+		return change, false, fmt.Errorf("invalid configuration: %w", err)
+	}
+
+	return pendingChange{name: name, pool: target, spec: spec}, spec != current, nil
+}
@@ -0,0 +1,253 @@
+// Package debug serves live introspection of pools over HTTP, in the spirit of net/http/pprof.
+//
+// Pools are registered by name and the handler is mounted explicitly:
+//
+//	debug.Register("buffers", p)
+//	http.Handle("/debug/pools", debug.Handler())
+//
+// HandlerFor serves the pools of a pool.Registry instead, along with their labels.
+//
+// The handler lists every pool with its effective configuration, live statistics, L1 and ring buffer
+// occupancy and blocked callers. Pools with the outstanding profile enabled also list the objects that
+// weren't returned yet, with the stack of the Get that handed them out. The pool query parameter selects
+// a single pool, format=json renders JSON instead of text.
+package debug
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"runtime/pprof"
+	"sort"
+	"sync"
+
+	"github.com/AlexsanderHamir/PoolX/v2/pool"
+)
+
+// Pool is a pool that can be introspected, *pool.Pool[T] implements it for every T.
+type Pool interface {
+	GetExtendedStatsSnapshot() *pool.ExtendedPoolStatsSnapshot
+	GetConfigSpec() pool.ConfigSpec
+	WriteStats(w io.Writer, format pool.Format) error
+}
+
+var (
+	mu    sync.RWMutex
+	pools = make(map[string]Pool)
+)
+
+// Register makes a pool visible to the handler under name.
+// Returns an error if the name is empty or already taken.
+func Register(name string, p Pool) error {
+	if name == "" {
+		return fmt.Errorf("pool name is empty")
+	}
+
+	if p == nil {
+		return fmt.Errorf("pool %q is nil", name)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+
+	if _, ok := pools[name]; ok {
+		return fmt.Errorf("pool %q is already registered", name)
+	}
+
+	pools[name] = p
+	return nil
+}
+
+// Unregister removes the pool registered under name, if any.
+func Unregister(name string) {
+	mu.Lock()
+	defer mu.Unlock()
+
+	delete(pools, name)
+}
+
+// Handler returns the handler serving the pools registered with Register.
+func Handler() http.Handler {
+	return handler(registered)
+}
+
+// HandlerFor returns the handler serving the pools of the registry, along with their labels.
+func HandlerFor(r *pool.Registry) http.Handler {
+	return handler(func() []entry {
+		pools := r.Pools()
+		entries := make([]entry, len(pools))
+		for i, p := range pools {
+			entries[i] = entry{name: p.Name, labels: p.Labels, pool: p.Pool}
+		}
+		return entries
+	})
+}
+
+// entry is a pool served by the handler.
+type entry struct {
+	name   string
+	labels map[string]string
+	pool   Pool
+}
+
+// registered returns the pools registered with Register.
+func registered() []entry {
+	mu.RLock()
+	defer mu.RUnlock()
+
+	entries := make([]entry, 0, len(pools))
+	for name, p := range pools {
+		entries = append(entries, entry{name: name, pool: p})
+	}
+	return entries
+}
+
+// poolReport is what the handler shows for a single pool.
+type poolReport struct {
+	Name      string                          `json:"name"`
+	Labels    map[string]string               `json:"labels,omitempty"`
+	Config    pool.ConfigSpec                 `json:"config"`
+	Occupancy occupancy                       `json:"occupancy"`
+	Stats     *pool.ExtendedPoolStatsSnapshot `json:"stats"`
+
+	// Outstanding is only set for pools with the outstanding profile enabled.
+	Outstanding *outstanding `json:"outstanding,omitempty"`
+
+	pool Pool
+}
+
+// outstandingProfiler is implemented by pools that record their outstanding objects, *pool.Pool[T] returns
+// a nil profile when the outstanding profile is disabled.
+type outstandingProfiler interface {
+	OutstandingProfile() *pprof.Profile
+}
+
+// outstanding describes the objects handed out and not returned yet.
+type outstanding struct {
+	Profile string `json:"profile"`
+	Count   int    `json:"count"`
+
+	profile *pprof.Profile
+}
+
+// occupancy tells where the idle objects are and how many callers are blocked.
+type occupancy struct {
+	L1Length         int    `json:"l1_length"`
+	L1Capacity       int    `json:"l1_capacity"`
+	RingBufferLength int    `json:"ring_buffer_length"`
+	RingBufferCap    int    `json:"ring_buffer_capacity"`
+	InUse            uint64 `json:"in_use"`
+	BlockedReaders   int    `json:"blocked_readers"`
+	WaitingCallers   int    `json:"waiting_callers"`
+	QueuedWaiters    int    `json:"queued_waiters"`
+}
+
+func handler(entries func() []entry) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		reports, err := collect(entries(), r.URL.Query().Get("pool"))
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusNotFound)
+			return
+		}
+
+		if r.URL.Query().Get("format") == "json" {
+			w.Header().Set("Content-Type", "application/json")
+			enc := json.NewEncoder(w)
+			enc.SetIndent("", "  ")
+			_ = enc.Encode(reports)
+			return
+		}
+
+		w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+		for _, report := range reports {
+			writeText(w, report)
+		}
+	})
+}
+
+// collect builds the reports of the pools sorted by name, or of the named pool only.
+func collect(entries []entry, name string) ([]poolReport, error) {
+	sort.Slice(entries, func(i, j int) bool { return entries[i].name < entries[j].name })
+
+	reports := make([]poolReport, 0, len(entries))
+	for _, e := range entries {
+		if name == "" || e.name == name {
+			reports = append(reports, report(e))
+		}
+	}
+
+	if name != "" && len(reports) == 0 {
+		return nil, fmt.Errorf("pool %q is not registered", name)
+	}
+
+	return reports, nil
+}
+
+func report(e entry) poolReport {
+	stats := e.pool.GetExtendedStatsSnapshot()
+
+	var leases *outstanding
+	if profiler, ok := e.pool.(outstandingProfiler); ok {
+		if profile := profiler.OutstandingProfile(); profile != nil {
+			leases = &outstanding{Profile: profile.Name(), Count: profile.Count(), profile: profile}
+		}
+	}
+
+	return poolReport{
+		Name:   e.name,
+		Labels: e.labels,
+		Config: e.pool.GetConfigSpec(),
+		Occupancy: occupancy{
+			L1Length:         stats.L1Length,
+			L1Capacity:       stats.CurrentL1Capacity,
+			RingBufferLength: stats.RingBufferLength,
+			RingBufferCap:    stats.CurrentCapacity,
+			InUse:            stats.ObjectsInUse,
+			BlockedReaders:   stats.BlockedReaders,
+			WaitingCallers:   stats.WaitingCallers,
+			QueuedWaiters:    stats.Waiters,
+		},
+		Stats:       stats,
+		Outstanding: leases,
+		pool:        e.pool,
+	}
+}
+
+func writeText(w io.Writer, r poolReport) {
+	o := r.Occupancy
+
+	fmt.Fprintf(w, "# pool %s\n\n", r.Name)
+	if len(r.Labels) > 0 {
+		keys := make([]string, 0, len(r.Labels))
+		for k := range r.Labels {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+
+		fmt.Fprintf(w, "Labels:\n")
+		for _, k := range keys {
+			fmt.Fprintf(w, "  %s=%s\n", k, r.Labels[k])
+		}
+		fmt.Fprintln(w)
+	}
+	fmt.Fprintf(w, "Occupancy:\n")
+	fmt.Fprintf(w, "  L1 cache:        %d/%d\n", o.L1Length, o.L1Capacity)
+	fmt.Fprintf(w, "  Ring buffer:     %d/%d\n", o.RingBufferLength, o.RingBufferCap)
+	fmt.Fprintf(w, "  In use:          %d\n", o.InUse)
+	fmt.Fprintf(w, "  Blocked readers: %d\n", o.BlockedReaders)
+	fmt.Fprintf(w, "  Waiting callers: %d\n", o.WaitingCallers)
+	fmt.Fprintf(w, "  Queued waiters:  %d\n\n", o.QueuedWaiters)
+
+	config, _ := json.MarshalIndent(r.Config, "", "  ")
+	fmt.Fprintf(w, "Configuration:\n%s\n\n", config)
+
+	_ = r.pool.WriteStats(w, pool.FormatTable)
+	fmt.Fprintln(w)
+
+	if r.Outstanding != nil {
+		fmt.Fprintf(w, "Outstanding objects (%s):\n", r.Outstanding.Profile)
+		_ = r.Outstanding.profile.WriteTo(w, 1)
+		fmt.Fprintln(w)
+	}
+}
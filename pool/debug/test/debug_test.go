@@ -0,0 +1,210 @@
+package test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/AlexsanderHamir/PoolX/v2/pool"
+	"github.com/AlexsanderHamir/PoolX/v2/pool/debug"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type testObject struct {
+	value int
+}
+
+func newTestPool(t *testing.T) *pool.Pool[*testObject] {
+	t.Helper()
+
+	config, err := pool.NewPoolConfigBuilder[*testObject]().
+		SetPoolBasicConfigs(64, 128, true).
+		Build()
+	require.NoError(t, err)
+
+	obj, err := pool.NewPool(config,
+		func() *testObject { return &testObject{} },
+		func(obj *testObject) { obj.value = 0 },
+		func(obj *testObject) *testObject { dst := *obj; return &dst },
+	)
+	require.NoError(t, err)
+
+	p := obj.(*pool.Pool[*testObject])
+	t.Cleanup(func() { _ = p.Close() })
+
+	return p
+}
+
+func register(t *testing.T, name string, p debug.Pool) {
+	t.Helper()
+
+	require.NoError(t, debug.Register(name, p))
+	t.Cleanup(func() { debug.Unregister(name) })
+}
+
+func serve(t *testing.T, target string) *httptest.ResponseRecorder {
+	t.Helper()
+
+	rec := httptest.NewRecorder()
+	debug.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, target, nil))
+	return rec
+}
+
+func TestRegisterRejectsInvalidPools(t *testing.T) {
+	p := newTestPool(t)
+	register(t, "register", p)
+
+	assert.Error(t, debug.Register("", p))
+	assert.Error(t, debug.Register("nil", nil))
+	assert.Error(t, debug.Register("register", p))
+}
+
+func TestHandlerText(t *testing.T) {
+	p := newTestPool(t)
+	register(t, "text", p)
+
+	obj, err := p.Get()
+	require.NoError(t, err)
+	defer func() { require.NoError(t, p.Put(obj)) }()
+
+	rec := serve(t, "/debug/pools?pool=text")
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	body := rec.Body.String()
+	assert.Contains(t, body, "# pool text")
+	assert.Contains(t, body, "L1 cache:")
+	assert.Contains(t, body, "Ring buffer:")
+	assert.Contains(t, body, "Blocked readers: 0")
+	assert.Contains(t, body, `"initial_capacity": 64`)
+	assert.Contains(t, body, "STATISTIC")
+}
+
+func TestHandlerJSON(t *testing.T) {
+	first, second := newTestPool(t), newTestPool(t)
+	register(t, "json-b", second)
+	register(t, "json-a", first)
+
+	obj, err := first.Get()
+	require.NoError(t, err)
+	defer func() { require.NoError(t, first.Put(obj)) }()
+
+	rec := serve(t, "/debug/pools?format=json")
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "application/json", rec.Header().Get("Content-Type"))
+
+	var reports []struct {
+		Name   string `json:"name"`
+		Config struct {
+			HardLimit int `json:"hard_limit"`
+		} `json:"config"`
+		Occupancy struct {
+			L1Capacity int    `json:"l1_capacity"`
+			InUse      uint64 `json:"in_use"`
+		} `json:"occupancy"`
+		Stats struct {
+			TotalGets uint64 `json:"total_gets"`
+		} `json:"stats"`
+	}
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &reports))
+
+	var names []string
+	for _, r := range reports {
+		names = append(names, r.Name)
+	}
+	assert.Subset(t, names, []string{"json-a", "json-b"})
+	assert.Less(t, indexOf(names, "json-a"), indexOf(names, "json-b"), "pools are sorted by name")
+
+	a := reports[indexOf(names, "json-a")]
+	assert.Equal(t, 128, a.Config.HardLimit)
+	assert.Equal(t, uint64(1), a.Occupancy.InUse)
+	assert.Equal(t, uint64(1), a.Stats.TotalGets)
+	assert.Positive(t, a.Occupancy.L1Capacity)
+}
+
+func TestHandlerUnknownPool(t *testing.T) {
+	rec := serve(t, "/debug/pools?pool=missing")
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+}
+
+func indexOf(names []string, name string) int {
+	for i, n := range names {
+		if n == name {
+			return i
+		}
+	}
+	return -1
+}
+
+func TestHandlerForRegistry(t *testing.T) {
+	config, err := pool.NewPoolConfigBuilder[*testObject]().
+		SetName("orders").
+		Build()
+	require.NoError(t, err)
+
+	obj, err := pool.NewPool(config,
+		func() *testObject { return &testObject{} },
+		func(obj *testObject) { obj.value = 0 },
+		func(obj *testObject) *testObject { dst := *obj; return &dst },
+	)
+	require.NoError(t, err)
+	p := obj.(*pool.Pool[*testObject])
+	defer p.Close()
+
+	registry := pool.NewRegistry()
+	require.NoError(t, registry.Register(p, map[string]string{"type": "order"}))
+
+	rec := httptest.NewRecorder()
+	debug.HandlerFor(registry).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/debug/pools", nil))
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	body := rec.Body.String()
+	assert.Contains(t, body, "# pool orders")
+	assert.Contains(t, body, "type=order")
+}
+
+func TestHandlerOutstandingObjects(t *testing.T) {
+	config, err := pool.NewPoolConfigBuilder[*testObject]().
+		SetName("debug-outstanding").
+		SetOutstandingProfile(true).
+		Build()
+	require.NoError(t, err)
+
+	obj, err := pool.NewPool(config,
+		func() *testObject { return &testObject{} },
+		func(obj *testObject) { obj.value = 0 },
+		func(obj *testObject) *testObject { dst := *obj; return &dst },
+	)
+	require.NoError(t, err)
+	p := obj.(*pool.Pool[*testObject])
+	defer p.Close()
+	register(t, "outstanding", p)
+
+	leased, err := p.Get()
+	require.NoError(t, err)
+	defer func() { require.NoError(t, p.Put(leased)) }()
+
+	body := serve(t, "/debug/pools?pool=outstanding").Body.String()
+	assert.Contains(t, body, "Outstanding objects (poolx.outstanding.debug-outstanding):")
+	assert.Contains(t, body, "TestHandlerOutstandingObjects")
+
+	var reports []struct {
+		Outstanding *struct {
+			Profile string `json:"profile"`
+			Count   int    `json:"count"`
+		} `json:"outstanding"`
+	}
+	require.NoError(t, json.Unmarshal(serve(t, "/debug/pools?pool=outstanding&format=json").Body.Bytes(), &reports))
+	require.Len(t, reports, 1)
+	require.NotNil(t, reports[0].Outstanding)
+	assert.Equal(t, 1, reports[0].Outstanding.Count)
+
+	register(t, "plain", newTestPool(t))
+
+	reports = nil
+	require.NoError(t, json.Unmarshal(serve(t, "/debug/pools?pool=plain&format=json").Body.Bytes(), &reports))
+	require.Len(t, reports, 1)
+	assert.Nil(t, reports[0].Outstanding, "pools without the outstanding profile don't report outstanding objects")
+}
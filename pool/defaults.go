@@ -29,6 +29,9 @@ const (
 	defaultPreReadBlockHookAttempts                       = 3
 	defaultEnableChannelGrowth                            = true
 	defaultEnableStats                                    = false
+	defaultBackgroundReplenish                            = false
+	defaultReplenishInterval                              = 50 * time.Millisecond
+	defaultCircuitBreakerOpenDuration                     = 5 * time.Second
 	Block                                                 = false
 	RTimeout                                              = 0
 	WTimeout                                              = 0
@@ -103,6 +106,8 @@ var defaultFastPath = &fastPathParameters{
 	growthEventsTrigger:      defaultGrowthEventsTrigger,
 	shrinkEventsTrigger:      defaultShrinkEventsTrigger,
 	preReadBlockHookAttempts: defaultPreReadBlockHookAttempts,
+	backgroundReplenish:      defaultBackgroundReplenish,
+	replenishInterval:        defaultReplenishInterval,
 	growth:                   defaultGrowthParameters,
 	shrink:                   defaultShrinkParameters,
 }
@@ -0,0 +1,52 @@
+package pool
+
+import (
+	"expvar"
+	"fmt"
+	"sync"
+)
+
+// ExpvarSource is a pool whose statistics and configuration can be published as an expvar,
+// *Pool[T] implements it for every T.
+type ExpvarSource interface {
+	// GetPoolStatsSnapshot returns a snapshot of the pool statistics.
+	GetPoolStatsSnapshot() *PoolStatsSnapshot
+	// GetConfigSpec returns the serializable view of the pool configuration.
+	GetConfigSpec() ConfigSpec
+}
+
+// expvarMu serializes the publications, expvar.Publish panics if the name is taken between
+// the check and the publication.
+var expvarMu sync.Mutex
+
+// expvarPool is the value published for a pool under /debug/vars.
+type expvarPool struct {
+	Stats  *PoolStatsSnapshot `json:"stats"`
+	Config ConfigSpec         `json:"config"`
+}
+
+// PublishExpvar publishes the statistics and the effective configuration of the pool as an expvar
+// under name, so they are served as JSON by the /debug/vars handler of the expvar package.
+// Returns an error if a variable with the same name is already published, expvar doesn't
+// support removing variables, so a name can only be used once per process.
+func PublishExpvar(name string, p ExpvarSource) error {
+	if p == nil {
+		return fmt.Errorf("cannot publish a nil pool as %q", name)
+	}
+
+	expvarMu.Lock()
+	defer expvarMu.Unlock()
+
+	if expvar.Get(name) != nil {
+		return fmt.Errorf("expvar %q is already published", name)
+	}
+
+	expvar.Publish(name, expvar.Func(func() any {
+		return expvarPool{
+			Stats:  p.GetPoolStatsSnapshot(),
+			Config: p.GetConfigSpec(),
+		}
+	}))
+
+	return nil
+}
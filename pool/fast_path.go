@@ -75,6 +75,9 @@ func (p *Pool[T]) tryL1ResizeIfTriggered() error {
 
 	p.stats.currentL1Capacity = newCap
 	p.stats.lastL1ResizeAtGrowthNum = p.stats.totalGrowthEvents
+	p.logger.Debug("L1 cache grew", "old_capacity", currentCap, "new_capacity", newCap)
+	p.listener.OnL1Resize(currentCap, newCap)
+	p.metrics.l1Capacity.Set(float64(newCap))
 
 	return p.drainOldChannel(oldCh, newCh)
 }
@@ -82,20 +85,26 @@ func (p *Pool[T]) tryL1ResizeIfTriggered() error {
 // tryGetFromL1 attempts to retrieve an object from the L1 cache channel.
 // Returns the object and true if found, otherwise returns zero value and false.
 func (p *Pool[T]) tryGetFromL1(locked bool) (zero T, found bool) {
-	var chPtr *chan T = p.cacheL1
-
-	if !locked {
-		p.mu.RLock()
-		chPtr = p.cacheL1
-		p.mu.RUnlock()
+	if locked {
+		return p.receiveFromL1(*p.cacheL1)
 	}
 
+	p.mu.RLock()
+	chPtr := p.cacheL1
+	p.mu.RUnlock()
+
+	return p.receiveFromL1(*chPtr)
+}
+
+// receiveFromL1 takes an object from ch, an L1 channel read under the pool lock, without blocking.
+func (p *Pool[T]) receiveFromL1(ch chan T) (zero T, found bool) {
 	select {
-	case obj, ok := <-*chPtr:
+	case obj, ok := <-ch:
 		if !ok {
 			return zero, false
 		}
 		p.stats.totalGets.Add(1)
+		p.metrics.getsL1.Add(1)
 
 		return obj, true
 	default:
@@ -106,13 +115,13 @@ func (p *Pool[T]) tryGetFromL1(locked bool) (zero T, found bool) {
 // tryFastPathPut attempts to quickly return an object to the L1 cache channel using a non-blocking
 // select operation. If successful, it updates hit statistics and returns true.
 // If the channel is full, it returns false to indicate a miss.
-func (p *Pool[T]) tryFastPathPut(obj T) bool {
-	defer func() bool {
+func (p *Pool[T]) tryFastPathPut(obj T) (ok bool) {
+	// Sending on the L1 channel of a closed pool panics, Put falls back to the slow path.
+	// The slow path must not be taken here as well, the object would be counted as returned twice.
+	defer func() {
 		if r := recover(); r != nil {
-			err := p.slowPathPut(obj)
-			return err == nil
+			ok = false
 		}
-		return true
 	}()
 
 	p.mu.RLock()
@@ -184,7 +193,7 @@ func (p *Pool[T]) shouldShrinkFastPath() bool {
 func (p *Pool[T]) adjustFastPathShrinkTarget(currentCap int) int {
 	cfg := p.config.fastPath.shrink
 	newCap := currentCap * (100 - cfg.shrinkPercent) / 100
-	inUse := int(p.stats.totalGets.Load() - (p.stats.FastReturnHit.Load() + p.stats.FastReturnMiss.Load()))
+	inUse := int(p.stats.totalGets.Load() - p.stats.totalReturns())
 
 	if newCap < cfg.minCapacity {
 		return cfg.minCapacity
@@ -217,13 +226,10 @@ func (p *Pool[T]) copyObjectsToNewChannel(oldCh, newCh chan T, count int) int {
 }
 
 // createNewL1Channel creates a new L1 channel with the specified capacity
-// and copies objects from the old channel if possible.
+// and copies objects from the old channel if possible. The old channel must already be closed,
+// so that no object can be stored in it after the copy.
 func (p *Pool[T]) createNewL1Channel(oldCh chan T, newCapacity, inUse int) chan T {
 	availableObjsToCopy := newCapacity - inUse
-	if availableObjsToCopy <= 0 {
-		return nil
-	}
-
 	copyCount := min(availableObjsToCopy, len(oldCh))
 	newL1 := make(chan T, newCapacity)
 
@@ -243,20 +249,43 @@ func (p *Pool[T]) updateShrinkStats(newCapacity int) {
 // shrinkFastPath shrinks the L1 cache channel by creating a new channel with the specified capacity
 // and copying objects from the old channel if possible.
 func (p *Pool[T]) shrinkFastPath(newCapacity, inUse int) {
-	defer func() {
-		if r := recover(); r != nil {
-		}
-	}()
+	if newCapacity-inUse <= 0 {
+		return
+	}
 
 	chPtr := p.cacheL1
 	ch := *chPtr
 
-	newL1 := p.createNewL1Channel(ch, newCapacity, inUse)
-	if newL1 == nil {
+	// Closing first means no Put can store an object in the old channel after it was copied,
+	// the remaining objects can still be received from the closed channel.
+	if !closeL1(ch) {
 		return
 	}
 
-	close(ch)
+	oldCapacity := p.stats.currentL1Capacity
+	newL1 := p.createNewL1Channel(ch, newCapacity, inUse)
 	p.cacheL1 = &newL1
 	p.updateShrinkStats(newCapacity)
+	p.logger.Debug("L1 cache shrank", "old_capacity", oldCapacity, "new_capacity", newCapacity)
+	p.listener.OnL1Resize(oldCapacity, newCapacity)
+	p.metrics.l1Capacity.Set(float64(newCapacity))
+	p.destroyDroppedL1Items(ch)
+}
+
+// destroyDroppedL1Items drains the objects that didn't fit in the new L1 channel,
+// accounting for them as destroyed and releasing their slab membership.
+func (p *Pool[T]) destroyDroppedL1Items(oldCh chan T) {
+	destroyed := 0
+	for obj := range oldCh {
+		destroyed++
+		if p.slabs != nil {
+			p.slabs.release(obj)
+		}
+	}
+
+	if destroyed > 0 {
+		p.stats.objectsDestroyed += destroyed
+		p.listener.OnDestroy(destroyed)
+		p.metrics.destructions.Add(uint64(destroyed))
+	}
 }
@@ -38,13 +38,15 @@ func (p *Pool[T]) needsToShrinkToHardLimit(newCapacity int) bool {
 // It handles capacity calculations, validation, and performs the actual shrinking operations
 // while maintaining proper logging and statistics.
 func (p *Pool[T]) shrinkExecution() {
+	defer p.startOp(opShrink, noOp).end()
+
 	currentCap := p.stats.currentCapacity
 	newCapacity := int(currentCap) * (100 - p.config.shrink.shrinkPercent) / 100
 	if !p.shouldShrinkMainPool(currentCap, newCapacity) {
 		return
 	}
 
-	inUse := int(p.stats.totalGets.Load() - (p.stats.FastReturnHit.Load() + p.stats.FastReturnMiss.Load()))
+	inUse := int(p.stats.totalGets.Load() - p.stats.totalReturns())
 	newCapacity = p.adjustMainShrinkTarget(newCapacity, inUse)
 	p.performShrink(newCapacity, inUse)
 
@@ -63,6 +65,8 @@ func (p *Pool[T]) shrinkExecution() {
 // It preserves in-use objects and updates pool statistics.
 func (p *Pool[T]) performShrink(newCapacity, inUse int) {
 	if !p.canShrink(newCapacity, inUse) {
+		p.logger.Debug("shrink skipped, objects in use exceed the new capacity",
+			"capacity", p.stats.currentCapacity, "new_capacity", newCapacity, "in_use", inUse)
 		return
 	}
 
@@ -71,15 +75,36 @@ func (p *Pool[T]) performShrink(newCapacity, inUse int) {
 
 	totalItems := p.pool.Length(false)
 	destroyedCount := totalItems - itemsToKeep
+
+	if err := p.migrateItems(newRingBuffer, itemsToKeep); err != nil {
+		p.logger.Warn("shrink migration failed, keeping the current ring buffer",
+			"capacity", p.stats.currentCapacity, "new_capacity", newCapacity, "items_to_keep", itemsToKeep, "error", err)
+		return
+	}
+
 	if destroyedCount > 0 {
 		p.stats.objectsDestroyed += destroyedCount
 	}
 
-	if err := p.migrateItems(newRingBuffer, itemsToKeep); err != nil {
+	p.releaseDroppedItems()
+	p.finalizeShrink(newRingBuffer, newCapacity, max(destroyedCount, 0))
+}
+
+// releaseDroppedItems releases the slab membership of the items that were not migrated
+// and are about to be discarded along with the old buffer.
+func (p *Pool[T]) releaseDroppedItems() {
+	if p.slabs == nil {
 		return
 	}
 
-	p.finalizeShrink(newRingBuffer, newCapacity)
+	part1, part2, err := p.pool.GetAllView()
+	if err != nil {
+		p.logger.Warn("failed to release the slabs of objects dropped by shrink", "error", err)
+		return
+	}
+
+	p.releaseObjects(part1)
+	p.releaseObjects(part2)
 }
 
 // canShrink checks if the pool can be shrunk based on the new capacity and in-use objects
@@ -92,6 +117,7 @@ func (p *Pool[T]) canShrink(newCapacity, inUse int) bool {
 func (p *Pool[T]) createShrinkBuffer(newCapacity int) *ringbuffer.RingBuffer[T] {
 	newRingBuffer := ringbuffer.New[T](newCapacity)
 	newRingBuffer.CopyConfig(p.pool)
+	p.boundReads(newRingBuffer)
 	return newRingBuffer
 }
 
@@ -133,13 +159,24 @@ func (p *Pool[T]) writeItemsToNewBuffer(newRingBuffer *ringbuffer.RingBuffer[T],
 }
 
 // finalizeShrink updates the pool with the new buffer and updates statistics
-func (p *Pool[T]) finalizeShrink(newRingBuffer *ringbuffer.RingBuffer[T], newCapacity int) {
+func (p *Pool[T]) finalizeShrink(newRingBuffer *ringbuffer.RingBuffer[T], newCapacity, destroyed int) {
+	oldCapacity := p.stats.currentCapacity
+
 	p.pool.Close()
 	p.pool = newRingBuffer
 	p.stats.currentCapacity = newCapacity
 	p.stats.totalShrinkEvents++
 	p.stats.lastShrinkTime = time.Now()
 	p.stats.consecutiveShrinks++
+
+	p.logger.Info("pool shrank", "old_capacity", oldCapacity, "new_capacity", newCapacity, "destroyed", destroyed)
+	p.listener.OnShrink(oldCapacity, newCapacity, destroyed)
+	p.metrics.shrinkEvents.Add(1)
+	p.metrics.capacity.Set(float64(newCapacity))
+	if destroyed > 0 {
+		p.listener.OnDestroy(destroyed)
+		p.metrics.destructions.Add(uint64(destroyed))
+	}
 }
 
 // shouldShrinkMainPool determines if the main pool should be shrunk based on various conditions:
@@ -221,6 +258,7 @@ func (p *Pool[T]) createNewBuffer(newCapacity int) *ringbuffer.RingBuffer[T] {
 		return nil
 	}
 	newRingBuffer.CopyConfig(p.pool)
+	p.boundReads(newRingBuffer)
 	return newRingBuffer
 }
 
@@ -268,6 +306,8 @@ func (p *Pool[T]) fillRemainingCapacity(newCapacity int) error {
 // and the creation/population of the new buffer. It's the main entry point for
 // capacity changes in the pool.
 func (p *Pool[T]) updatePoolCapacity(newCapacity int) error {
+	defer p.startOp(opUpdateCapacity, opGrow).end()
+
 	if p.needsToShrinkToHardLimit(newCapacity) {
 		newCapacity = p.config.hardLimit
 		p.isGrowthBlocked.Store(true)
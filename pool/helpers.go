@@ -3,7 +3,7 @@ package pool
 import (
 	"errors"
 	"fmt"
-	"log"
+	"runtime/trace"
 	"time"
 
 	ringbufferInternalErrs "github.com/AlexsanderHamir/ringbuffer/errors"
@@ -17,21 +17,12 @@ func getShrinkDefaultsMap() map[AggressivenessLevel]*shrinkDefaults {
 // setPoolAndBuffer attempts to store an object in either the L1 cache or the main pool.
 // It returns the remaining fast path capacity and any error that occurred.
 func (p *Pool[T]) setPoolAndBuffer(obj T, fastPathRemaining int) (int, error) {
-	defer func() {
-		if r := recover(); r != nil {
-		}
-	}()
-
 	chPtr := p.cacheL1
 	ch := *chPtr
 
-	if fastPathRemaining > 0 {
-		select {
-		case ch <- obj:
-			fastPathRemaining--
-			return fastPathRemaining, nil
-		default:
-		}
+	if fastPathRemaining > 0 && trySendToL1(ch, obj) {
+		fastPathRemaining--
+		return fastPathRemaining, nil
 	}
 
 	// Store in main pool
@@ -42,10 +33,39 @@ func (p *Pool[T]) setPoolAndBuffer(obj T, fastPathRemaining int) (int, error) {
 	return fastPathRemaining, nil
 }
 
+// trySendToL1 stores obj in ch without blocking. Returns false if ch is full, or if it was closed
+// because L1 was resized or the pool closed concurrently, in which case the caller must store obj elsewhere.
+func trySendToL1[T any](ch chan T, obj T) (sent bool) {
+	defer func() {
+		if recover() != nil {
+			sent = false
+		}
+	}()
+
+	select {
+	case ch <- obj:
+		return true
+	default:
+		return false
+	}
+}
+
+// closeL1 closes ch, returns false if it was already closed by Close.
+func closeL1[T any](ch chan T) (closed bool) {
+	defer func() {
+		if recover() != nil {
+			closed = false
+		}
+	}()
+
+	close(ch)
+	return true
+}
+
 // calculateUtilization calculates the current utilization percentage of the pool.
 // Returns 0 if there are no objects in the pool or if the L1 cache is nil.
 func (p *Pool[T]) calculateUtilization() int {
-	inUse := p.stats.totalGets.Load() - (p.stats.FastReturnHit.Load() + p.stats.FastReturnMiss.Load())
+	inUse := p.stats.totalGets.Load() - p.stats.totalReturns()
 	return (int(inUse) / p.pool.Capacity()) * 100
 }
 
@@ -88,7 +108,7 @@ func (p *Pool[T]) isGrowthNeeded(fillTarget int) bool {
 
 func (p *Pool[T]) poolGrowthNeeded(fillTarget int) (ableToGrow bool, err error) {
 	if p.isGrowthBlocked.Load() {
-		return false, errGrowthBlocked
+		return false, p.growthBlocked()
 	}
 
 	if p.isGrowthNeeded(fillTarget) {
@@ -120,7 +140,7 @@ func (p *Pool[T]) getItemsToMove(fillTarget int) ([]T, []T, error) {
 func (p *Pool[T]) moveItemsToL1(items []T) error {
 	defer func() {
 		if r := recover(); r != nil {
-			log.Printf("[MOVE] panic on move items to L1: %v", r)
+			p.logger.Error("recovered panic moving items to L1", "panic", r)
 		}
 	}()
 
@@ -142,6 +162,8 @@ func (p *Pool[T]) moveItemsToL1(items []T) error {
 // refill attempts to refill the L1 cache with objects from the pool.
 // Returns the number of items moved, number of items failed, and any error that occurred.
 func (p *Pool[T]) refill(fillTarget int) error {
+	defer p.startOp(opRefill, noOp).end()
+
 	ableToGrow, err := p.poolGrowthNeeded(fillTarget)
 	if !ableToGrow && err != nil {
 		return err
@@ -173,6 +195,13 @@ func (p *Pool[T]) createOnDemand(fillTarget int, spaceAvailable int) error {
 		return nil
 	}
 
+	defer p.startOp(opAllocate, noOp).end()
+
+	createdBefore := p.stats.objectsCreated
+	defer func() {
+		p.stats.onDemandCreations.Add(uint64(p.stats.objectsCreated - createdBefore))
+	}()
+
 	return p.populateL1OrBuffer(allocAmount)
 }
 
@@ -215,39 +244,96 @@ func (p *Pool[T]) handleShrinkCooldown(shrinkCooldown time.Duration) (cooldownAc
 	return timeSinceLastShrink < shrinkCooldown
 }
 
+// tryRefill refills L1, growing the pool if needed. The caller must hold the write lock,
+// growing replaces the ring buffer and the capacity read by the snapshots.
 func (p *Pool[T]) tryRefill(fillTarget int) (bool, error) {
+	p.stats.refillAttempts.Add(1)
+
 	err := p.refill(fillTarget)
 	if err != nil {
+		p.stats.refillFailures.Add(1)
+		if errors.Is(err, errGrowthBlocked) {
+			p.stats.refillGrowthBlocked.Add(1)
+		}
 		return false, err
 	}
 
+	p.stats.refillSuccesses.Add(1)
 	return true, nil
 }
 
 // SlowPath retrieves an object from the ring buffer. It blocks if the ring buffer is empty
 // and the ring buffer is in blocking mode. We always try to refill the ring buffer before
-// calling the slow path.
+// calling the slow path. A blocking caller counts against the max waiters and the queue-time budget.
 func (p *Pool[T]) SlowPathGet() (obj T, err error) {
+	obj, _, err = p.slowPathGet()
+	return obj, err
+}
+
+// slowPathGet is SlowPathGet, it also reports whether the caller found the ring buffer empty
+// and may have blocked waiting for an object to be returned.
+//
+// A caller that may block is admitted and timed like any other wait, it fails with ErrOverloaded when
+// it isn't admitted or its queue-time budget expired. The read timeout of the ring buffer is capped at
+// the budget, see boundReads, so a blocked read returns once the budget is used up.
+func (p *Pool[T]) slowPathGet() (obj T, blocked bool, err error) {
 	const maxRetries = 5
 	const retryDelay = 10 * time.Millisecond
 
+	span := p.startOp(opSlowPathGet, noOp)
+	defer span.end()
+
+	p.stats.slowPathGets.Add(1)
+
+	var (
+		waitStart time.Time
+		waiting   bool
+	)
+	defer func() {
+		if waiting {
+			p.leaveWait(waitStart)
+		}
+	}()
+
 	for i := range maxRetries {
+		if i > 0 {
+			p.stats.slowPathRetries.Add(1)
+		}
+
 		p.mu.RLock()
 		pool := p.pool
 		p.mu.RUnlock()
 
+		empty := pool.Length(false) == 0
+		blocked = blocked || empty
+
+		if empty && !waiting && p.config.ringBufferConfig.Block {
+			start, admitted := p.enterWait()
+			if !admitted {
+				return obj, blocked, p.overloaded()
+			}
+			waitStart, waiting = start, true
+		}
+
 		obj, err = pool.GetOne()
 		if err == nil {
 			p.stats.totalGets.Add(1)
-			return obj, nil
+			p.metrics.getsRingBuffer.Add(1)
+			return obj, blocked, nil
+		}
+
+		if waiting && p.budgetExpired(waitStart) {
+			return obj, blocked, p.outOfBudget()
 		}
 
 		if i < maxRetries-1 {
+			retry := trace.StartRegion(span.ctx, "poolx.slow_path_get.retry")
 			time.Sleep(retryDelay)
+			retry.End()
 		}
 	}
 
-	return obj, fmt.Errorf("%w: %w", errRingBufferFailed, err)
+	return obj, blocked, fmt.Errorf("%w: %w", errRingBufferFailed, err)
 }
 
 func (p *Pool[T]) RingBufferCapacity() int {
@@ -260,7 +346,7 @@ func (p *Pool[T]) RingBufferLength() int {
 
 func (p *Pool[T]) hasOutstandingObjects() bool {
 	totalGets := p.stats.totalGets.Load()
-	totalReturns := p.stats.FastReturnHit.Load() + p.stats.FastReturnMiss.Load()
+	totalReturns := p.stats.totalReturns()
 	return totalReturns < totalGets
 }
 
@@ -272,7 +358,7 @@ func (p *Pool[T]) closeAsync() {
 	attempts := 0
 
 	for attempts < maxAttempts {
-		totalReturns := p.stats.FastReturnHit.Load() + p.stats.FastReturnMiss.Load()
+		totalReturns := p.stats.totalReturns()
 		if totalReturns >= p.stats.totalGets.Load() {
 			p.performClosure()
 			return
@@ -293,37 +379,87 @@ func (p *Pool[T]) closeAsync() {
 func (p *Pool[T]) performClosure() {
 	p.shrinkCond.Signal()
 	p.cancel()
+	p.destroyReserved(p.waiters.close())
 	p.pool.Close()
 	p.cleanupCacheL1()
+
+	if p.slabs != nil {
+		p.slabs.reset()
+	}
+}
+
+// destroyReserved accounts for the objects that were reserved for queued waiters when the pool closed.
+func (p *Pool[T]) destroyReserved(dropped []T) {
+	if len(dropped) == 0 {
+		return
+	}
+
+	p.mu.Lock()
+	p.stats.objectsDestroyed += len(dropped)
+	p.mu.Unlock()
+
+	p.listener.OnDestroy(len(dropped))
+	p.metrics.destructions.Add(uint64(len(dropped)))
 }
 
 // GetBlockedReaders returns the number of readers currently blocked waiting for objects
 func (p *Pool[T]) GetBlockedReaders() int {
-	return p.pool.GetBlockedReaders()
+	p.mu.RLock()
+	pool := p.pool
+	p.mu.RUnlock()
+
+	return pool.GetBlockedReaders()
 }
 
 // tryRefillAndGetL1 attempts to refill the pool, and get an object from L1 cache.
 // It will grow in case it's allowed and needed.
-func (p *Pool[T]) tryRefillAndFromGetL1() (zero T, canProceed bool) {
+// Waiting for another goroutine's refill is admitted and timed like any other wait, err is ErrOverloaded
+// when the caller isn't admitted or its queue-time budget expired before the refill produced an object.
+func (p *Pool[T]) tryRefillAndFromGetL1() (zero T, path GetPath, canProceed bool, err error) {
 	select {
 	case p.refillSemaphore <- struct{}{}:
-		defer func() {
-			p.refillCond.Broadcast()
-			<-p.refillSemaphore
-		}()
+		defer p.releaseRefill()
 		obj, canProceed := p.handleRefillScenarios()
-		return obj, canProceed
+		return obj, GetPathRefill, canProceed, nil
 	default:
-		p.refillCond.L.Lock()
-		p.refillCond.Wait()
-		p.refillCond.L.Unlock()
+		start, admitted := p.enterWait()
+		if !admitted {
+			return zero, GetPathFailed, false, p.overloaded()
+		}
+
+		span := p.startOp(opRefillWait, noOp)
+		p.waitForRefill()
+		span.end()
+		p.leaveWait(start)
 
 		if obj, found := p.tryGetFromL1(false); found {
-			return obj, true
+			return obj, GetPathRefillWait, true, nil
 		}
 
-		return zero, false
+		if p.budgetExpired(start) {
+			return zero, GetPathFailed, false, p.outOfBudget()
+		}
+
+		return zero, GetPathFailed, false, nil
+	}
+}
+
+// waitForRefill waits on refillCond for the refill in progress, at most for the queue-time budget.
+func (p *Pool[T]) waitForRefill() {
+	p.refillCond.L.Lock()
+	defer p.refillCond.L.Unlock()
+
+	if d := p.config.queueTimeBudget; d > 0 {
+		// the lock makes sure the broadcast can't slip in before Wait
+		timer := time.AfterFunc(d, func() {
+			p.refillCond.L.Lock()
+			p.refillCond.Broadcast()
+			p.refillCond.L.Unlock()
+		})
+		defer timer.Stop()
 	}
+
+	p.refillCond.Wait()
 }
 
 // tryGetFromL1IfWellStocked attempts to get an object from L1 cache if it's well stocked
@@ -339,22 +475,32 @@ func (p *Pool[T]) tryCreateAndGetFromL1(fillTarget int) (obj T, found bool) {
 	p.mu.Lock()
 	defer p.mu.Unlock()
 
+	if !p.createIfSpaceAvailable(fillTarget) {
+		return obj, false
+	}
+
+	return p.tryGetFromL1(true)
+}
+
+// createIfSpaceAvailable creates new objects on demand if the ring buffer still has room for them.
+// The caller must hold the write lock.
+func (p *Pool[T]) createIfSpaceAvailable(fillTarget int) bool {
 	spaceAvailable := p.pool.Capacity() - (p.stats.objectsCreated - p.stats.objectsDestroyed)
 	if spaceAvailable <= 0 {
-		return obj, false
+		return false
 	}
 
 	if err := p.createOnDemand(fillTarget, spaceAvailable); err != nil {
-		return obj, false
+		return false
 	}
 
-	return p.tryGetFromL1(true)
+	return true
 }
 
 // tryRefillAndGetFromL1 attempts to refill from main pool and get from L1 cache
 func (p *Pool[T]) tryRefillAndGetFromL1(fillTarget int) (obj T, found bool) {
-	p.mu.RLock()
-	defer p.mu.RUnlock()
+	p.mu.Lock()
+	defer p.mu.Unlock()
 
 	ableToRefill, err := p.tryRefill(fillTarget)
 	if !ableToRefill && err != nil {
@@ -416,6 +562,19 @@ func (p *Pool[T]) handleRefillFailure(refillError error) (T, bool) {
 	return zero, true
 }
 
+// usesWaitQueue reports whether exhausted GetPriority and GetAtomic callers wait in the wait queue, which is
+// the case whenever the ring buffer is blocking. Otherwise nobody waits for a returned object, callers only wait
+// on refillCond for a refill in progress and then read the ring buffer without blocking.
+func (p *Pool[T]) usesWaitQueue() bool {
+	return p.config.ringBufferConfig.Block
+}
+
+// queuesGetCallers reports whether exhausted Get callers wait in the wait queue, which is only the case in fair mode.
+// Otherwise they block in the ring buffer, served after the queued callers.
+func (p *Pool[T]) queuesGetCallers() bool {
+	return p.config.fairWaiting && p.usesWaitQueue()
+}
+
 func (p *Pool[T]) IsRingBufferShrunk() bool {
 	return p.stats.currentCapacity < p.config.initialCapacity
 }
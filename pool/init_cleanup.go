@@ -14,26 +14,33 @@ import (
 // statistics, shrink/growth parameters, fast path settings, and ring buffer configuration.
 // Returns a fully configured PoolConfig instance.
 func createDefaultConfig[T any]() *PoolConfig[T] {
+	// the defaults are copied, every pool adjusts its own parameters
+	copiedShrink := *defaultShrinkParameters
+	copiedGrowth := *defaultGrowthParameters
+	copiedFastPath := *defaultFastPath
+	copiedAllocationStrategy := *defaultAllocationStrategy
+
 	pgb := &poolConfigBuilder[T]{
 		config: &PoolConfig[T]{
 			initialCapacity: defaultPoolCapacity,
 			hardLimit:       defaultHardLimit,
-			shrink:          defaultShrinkParameters,
-			growth:          defaultGrowthParameters,
-			fastPath:        defaultFastPath,
+			shrink:          &copiedShrink,
+			growth:          &copiedGrowth,
+			fastPath:        &copiedFastPath,
 			ringBufferConfig: &config.RingBufferConfig[T]{
 				Block:    Block,
 				RTimeout: RTimeout,
 				WTimeout: WTimeout,
 			},
-			allocationStrategy: defaultAllocationStrategy,
+			allocationStrategy:         &copiedAllocationStrategy,
+			circuitBreakerOpenDuration: defaultCircuitBreakerOpenDuration,
 		},
 	}
 
 	pgb.config.shrink.ApplyDefaults(getShrinkDefaultsMap())
 
-	copiedShrink := *pgb.config.shrink
-	pgb.config.fastPath.shrink = &copiedShrink
+	copiedL1Shrink := *pgb.config.shrink
+	pgb.config.fastPath.shrink = &copiedL1Shrink
 	pgb.config.fastPath.shrink.minCapacity = defaultL1MinCapacity
 
 	return pgb.config
@@ -52,8 +59,14 @@ func initializePoolStats[T any](config *PoolConfig[T]) *poolStats {
 
 // validateType validates the provided allocator, cleaner, and cloneTemplate functions.
 // This is a critical validation as the pool requires pointer types for proper object management.
-// Returns an error if the allocator returns a non-pointer type.
-func validate[T any](allocator func() T, cleaner func(T), cloner func(T) T) error {
+// Returns an error if the allocator returns a non-pointer type, or if the allocator or cloner panics.
+func validate[T any](allocator func() T, cleaner func(T), cloner func(T) T) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("%w: during validation: %v", errCallbackPanicked, r)
+		}
+	}()
+
 	var zero T
 	if reflect.TypeOf(zero).Kind() != reflect.Ptr {
 		return fmt.Errorf("type T must be a pointer type, got %T", zero)
@@ -77,6 +90,17 @@ func validate[T any](allocator func() T, cleaner func(T), cloner func(T) T) erro
 	return nil
 }
 
+// allocateTemplate creates the template object cloned by cloneTemplate, turning a panic into an error.
+func allocateTemplate[T any](allocator func() T) (template T, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("%w: allocating the template: %v", errCallbackPanicked, r)
+		}
+	}()
+
+	return allocator(), nil
+}
+
 // initializePoolObject creates and initializes a new Pool instance with the provided
 // configuration, allocator, cleaner, and ring buffer. It sets up the L1 cache channel
 // and initializes all necessary synchronization primitives.
@@ -84,7 +108,11 @@ func validate[T any](allocator func() T, cleaner func(T), cloner func(T) T) erro
 func initializePoolObject[T any](config *PoolConfig[T], allocator func() T, cleaner func(T), cloneTemplate func(T) T, stats *poolStats, ringBuffer *ringbuffer.RingBuffer[T]) (*Pool[T], error) {
 	ch := make(chan T, config.fastPath.initialSize)
 
-	template := allocator()
+	template, err := allocateTemplate(allocator)
+	if err != nil {
+		return nil, err
+	}
+
 	poolObj := &Pool[T]{
 		cacheL1:         &ch,
 		refillSemaphore: make(chan struct{}, 1),
@@ -96,8 +124,44 @@ func initializePoolObject[T any](config *PoolConfig[T], allocator func() T, clea
 		pool:            ringBuffer,
 		template:        template,
 		refillCond:      sync.NewCond(&sync.Mutex{}),
+		waiters:         newWaitQueue[T](),
+		breaker:         newCircuitBreaker(config.circuitBreakerThreshold, config.circuitBreakerOpenDuration),
+	}
+
+	if config.allocationStrategy.SlabAllocation {
+		slabs, err := newSlabAllocator[T]()
+		if err != nil {
+			return nil, err
+		}
+		poolObj.slabs = slabs
+	}
+
+	if config.fastPath.backgroundReplenish {
+		poolObj.replenishSignal = make(chan struct{}, 1)
+	}
+
+	poolObj.listener = config.listener
+	if poolObj.listener == nil {
+		poolObj.listener = NopListener{}
+	}
+
+	if config.getLatencyHistograms {
+		poolObj.getLatency = &getLatency{}
+	}
+
+	if config.outstandingProfile {
+		poolObj.outstanding = outstandingProfile(config.name)
+	}
+
+	if config.statsHistoryInterval > 0 && config.statsHistorySize > 0 {
+		poolObj.history = newStatsHistory(config.statsHistorySize)
 	}
 
+	poolObj.logger = newPoolLogger(config.logger, config.name)
+	poolObj.metrics = newPoolMetrics(config.metricsSink, config.name)
+	poolObj.metrics.capacity.Set(float64(stats.currentCapacity))
+	poolObj.metrics.l1Capacity.Set(float64(stats.currentL1Capacity))
+
 	poolObj.shrinkCond = sync.NewCond(&poolObj.mu)
 	return poolObj, nil
 }
@@ -107,19 +171,28 @@ func initializePoolObject[T any](config *PoolConfig[T], allocator func() T, clea
 // how many objects should go to the L1 cache versus the main buffer.
 // Returns an error if object allocation or distribution fails.
 func (p *Pool[T]) populateL1OrBuffer(allocAmount int) error {
+	createdBefore := p.stats.objectsCreated
+	defer func() {
+		if created := p.stats.objectsCreated - createdBefore; created > 0 {
+			p.listener.OnAllocate(created)
+			p.metrics.allocations.Add(uint64(created))
+		}
+	}()
+
 	fillTarget := p.config.fastPath.initialSize * p.config.fastPath.fillAggressiveness / 100
 	fastPathRemaining := fillTarget
 
+	if p.slabs != nil {
+		return p.populateFromSlabs(allocAmount, fastPathRemaining)
+	}
+
 	for range allocAmount {
-		var obj T
-		if p.cloneTemplate != nil {
-			obj = p.cloneTemplate(p.template)
-		} else {
-			obj = p.allocator()
+		obj, err := p.newObject()
+		if err != nil {
+			return fmt.Errorf("failed to create object: %w", err)
 		}
 		p.stats.objectsCreated++
 
-		var err error
 		fastPathRemaining, err = p.setPoolAndBuffer(obj, fastPathRemaining)
 		if err != nil {
 			return fmt.Errorf("failed to set pool and buffer: %w", err)
@@ -128,6 +201,33 @@ func (p *Pool[T]) populateL1OrBuffer(allocAmount int) error {
 	return nil
 }
 
+// populateFromSlabs is the slab allocation counterpart of populateL1OrBuffer.
+// Objects are allocated in slabs of AllocAmount objects, the last slab may be smaller.
+func (p *Pool[T]) populateFromSlabs(allocAmount, fastPathRemaining int) error {
+	slabSize := p.config.allocationStrategy.AllocAmount
+
+	for allocAmount > 0 {
+		size := min(slabSize, allocAmount)
+		allocAmount -= size
+
+		objs, allocErr := p.slabs.allocate(size, p.initSlabMember)
+		for _, obj := range objs {
+			p.stats.objectsCreated++
+
+			var err error
+			fastPathRemaining, err = p.setPoolAndBuffer(obj, fastPathRemaining)
+			if err != nil {
+				return fmt.Errorf("failed to set pool and buffer: %w", err)
+			}
+		}
+
+		if allocErr != nil {
+			return fmt.Errorf("failed to create object: %w", allocErr)
+		}
+	}
+	return nil
+}
+
 // cleanupCacheL1 performs cleanup of the L1 cache by:
 // 1. Draining all objects from the cache
 // 2. Calling the cleaner function on each object
@@ -145,7 +245,7 @@ func (p *Pool[T]) cleanupCacheL1() {
 			if !ok {
 				return
 			}
-			p.cleaner(obj)
+			_ = p.callCleaner(obj)
 			obj = zero
 			_ = obj
 		default:
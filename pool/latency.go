@@ -0,0 +1,159 @@
+package pool
+
+import (
+	"math"
+	"sync/atomic"
+	"time"
+)
+
+// GetPath identifies how a Get call obtained its object, or that it failed.
+type GetPath int
+
+const (
+	GetPathL1Hit             GetPath = iota // the object was in the L1 cache
+	GetPathRefill                           // the caller refilled L1 itself
+	GetPathRefillWait                       // the caller waited on refillCond while another goroutine refilled L1
+	GetPathRingBuffer                       // the object was read from the ring buffer without blocking
+	GetPathRingBufferBlocked                // the caller blocked in the ring buffer until an object was returned
+	GetPathWaitQueue                        // the caller waited in the wait queue until an object was handed to it
+	GetPathFailed                           // Get returned an error
+	numGetPaths
+)
+
+var getPathNames = [numGetPaths]string{
+	GetPathL1Hit:             "l1_hit",
+	GetPathRefill:            "refill",
+	GetPathRefillWait:        "refill_wait",
+	GetPathRingBuffer:        "ring_buffer",
+	GetPathRingBufferBlocked: "ring_buffer_blocked",
+	GetPathWaitQueue:         "wait_queue",
+	GetPathFailed:            "failed",
+}
+
+func (g GetPath) String() string {
+	if g < 0 || g >= numGetPaths {
+		return "unknown"
+	}
+	return getPathNames[g]
+}
+
+// latencyBuckets are the upper bounds of the latency histogram buckets,
+// latencies above the last bound are counted in an extra unbounded bucket.
+var latencyBuckets = [...]time.Duration{
+	100 * time.Nanosecond, 250 * time.Nanosecond, 500 * time.Nanosecond,
+	time.Microsecond, 2500 * time.Nanosecond, 5 * time.Microsecond,
+	10 * time.Microsecond, 25 * time.Microsecond, 50 * time.Microsecond,
+	100 * time.Microsecond, 250 * time.Microsecond, 500 * time.Microsecond,
+	time.Millisecond, 2500 * time.Microsecond, 5 * time.Millisecond,
+	10 * time.Millisecond, 25 * time.Millisecond, 50 * time.Millisecond,
+	100 * time.Millisecond, 250 * time.Millisecond, 500 * time.Millisecond,
+	time.Second, 2500 * time.Millisecond, 5 * time.Second, 10 * time.Second,
+}
+
+// LatencyBucket is a bucket of a latency histogram, UpperBound is math.MaxInt64 for the unbounded bucket.
+type LatencyBucket struct {
+	UpperBound time.Duration `json:"upper_bound"`
+	Count      uint64        `json:"count"`
+}
+
+// LatencySnapshot is a snapshot of a latency histogram, bucket counts are not cumulative.
+type LatencySnapshot struct {
+	Count   uint64          `json:"count"`
+	Sum     time.Duration   `json:"sum"`
+	Max     time.Duration   `json:"max"`
+	Buckets []LatencyBucket `json:"buckets"`
+}
+
+// Mean returns the average latency, 0 if nothing was observed.
+func (s LatencySnapshot) Mean() time.Duration {
+	if s.Count == 0 {
+		return 0
+	}
+	return s.Sum / time.Duration(s.Count)
+}
+
+// Quantile returns an upper bound of the q quantile (0 < q <= 1): the upper bound of the bucket
+// it falls in, capped at the maximum observed latency. Returns 0 if nothing was observed.
+func (s LatencySnapshot) Quantile(q float64) time.Duration {
+	if s.Count == 0 {
+		return 0
+	}
+
+	rank := uint64(math.Ceil(q * float64(s.Count)))
+	var seen uint64
+	for _, bucket := range s.Buckets {
+		seen += bucket.Count
+		if seen >= rank {
+			return min(bucket.UpperBound, s.Max)
+		}
+	}
+
+	return s.Max
+}
+
+// latencyHistogram is a lock-free histogram with fixed buckets.
+type latencyHistogram struct {
+	buckets [len(latencyBuckets) + 1]atomic.Uint64
+	count   atomic.Uint64
+	sum     atomic.Int64
+	max     atomic.Int64
+}
+
+func (h *latencyHistogram) observe(d time.Duration) {
+	i := 0
+	for i < len(latencyBuckets) && d > latencyBuckets[i] {
+		i++
+	}
+
+	h.buckets[i].Add(1)
+	h.count.Add(1)
+	h.sum.Add(int64(d))
+
+	for {
+		current := h.max.Load()
+		if int64(d) <= current || h.max.CompareAndSwap(current, int64(d)) {
+			return
+		}
+	}
+}
+
+func (h *latencyHistogram) snapshot() LatencySnapshot {
+	s := LatencySnapshot{
+		Count:   h.count.Load(),
+		Sum:     time.Duration(h.sum.Load()),
+		Max:     time.Duration(h.max.Load()),
+		Buckets: make([]LatencyBucket, len(h.buckets)),
+	}
+
+	for i := range h.buckets {
+		upperBound := time.Duration(math.MaxInt64)
+		if i < len(latencyBuckets) {
+			upperBound = latencyBuckets[i]
+		}
+		s.Buckets[i] = LatencyBucket{UpperBound: upperBound, Count: h.buckets[i].Load()}
+	}
+
+	return s
+}
+
+// getLatency holds a latency histogram per Get path.
+type getLatency struct {
+	paths [numGetPaths]latencyHistogram
+}
+
+// observe records the latency of a Get call, failed calls are recorded under GetPathFailed whatever their path.
+func (l *getLatency) observe(path GetPath, err error, d time.Duration) {
+	if err != nil {
+		path = GetPathFailed
+	}
+	l.paths[path].observe(d)
+}
+
+// snapshot returns the histograms keyed by path name.
+func (l *getLatency) snapshot() map[string]LatencySnapshot {
+	snapshots := make(map[string]LatencySnapshot, numGetPaths)
+	for path := range numGetPaths {
+		snapshots[path.String()] = l.paths[path].snapshot()
+	}
+	return snapshots
+}
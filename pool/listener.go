@@ -0,0 +1,48 @@
+package pool
+
+import "time"
+
+// Listener receives the lifecycle events of a pool, registered through the builder with SetListener.
+//
+// Callbacks are invoked synchronously from the goroutine that triggered the event, some of them
+// while internal locks are held. They must be fast, safe for concurrent use, and must not call
+// Get, Put, Close or the stats snapshot methods on the pool.
+type Listener interface {
+	// OnGrow is called after the ring buffer capacity grew.
+	OnGrow(oldCapacity, newCapacity int)
+	// OnShrink is called after the ring buffer capacity shrank, destroyed is the number of idle objects dropped.
+	OnShrink(oldCapacity, newCapacity, destroyed int)
+	// OnL1Resize is called after the L1 cache channel was resized, either way.
+	OnL1Resize(oldCapacity, newCapacity int)
+	// OnAllocate is called after count new objects were created.
+	OnAllocate(count int)
+	// OnDestroy is called after count objects were dropped by the pool.
+	OnDestroy(count int)
+	// OnGrowthBlocked is called when the pool needed to grow but reached its hard limit.
+	OnGrowthBlocked()
+	// OnWaitStart is called when a caller starts waiting for an object: on another goroutine's refill,
+	// blocked in the ring buffer or in the wait queue.
+	OnWaitStart()
+	// OnWaitEnd is called when a caller stops waiting for an object, successfully or not.
+	OnWaitEnd(waited time.Duration)
+}
+
+// growthBlocked notifies the listener that growth was needed at the hard limit and returns errGrowthBlocked.
+func (p *Pool[T]) growthBlocked() error {
+	p.logger.Debug("growth blocked by the hard limit", "hard_limit", p.config.hardLimit)
+	p.listener.OnGrowthBlocked()
+	return errGrowthBlocked
+}
+
+// NopListener is a Listener that ignores every event. It can be embedded to implement
+// only the callbacks of interest.
+type NopListener struct{}
+
+func (NopListener) OnGrow(oldCapacity, newCapacity int)              {}
+func (NopListener) OnShrink(oldCapacity, newCapacity, destroyed int) {}
+func (NopListener) OnL1Resize(oldCapacity, newCapacity int)          {}
+func (NopListener) OnAllocate(count int)                             {}
+func (NopListener) OnDestroy(count int)                              {}
+func (NopListener) OnGrowthBlocked()                                 {}
+func (NopListener) OnWaitStart()                                     {}
+func (NopListener) OnWaitEnd(waited time.Duration)                   {}
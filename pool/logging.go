@@ -0,0 +1,28 @@
+package pool
+
+import (
+	"context"
+	"log/slog"
+)
+
+// newPoolLogger returns the logger used by a pool, with the pool name attached when it has one.
+// Pools without a configured logger log nothing.
+func newPoolLogger(logger *slog.Logger, name string) *slog.Logger {
+	if logger == nil {
+		return slog.New(discardHandler{})
+	}
+
+	if name != "" {
+		return logger.With(slog.String("pool", name))
+	}
+
+	return logger
+}
+
+// discardHandler is a slog.Handler that drops every record.
+type discardHandler struct{}
+
+func (discardHandler) Enabled(context.Context, slog.Level) bool  { return false }
+func (discardHandler) Handle(context.Context, slog.Record) error { return nil }
+func (h discardHandler) WithAttrs([]slog.Attr) slog.Handler      { return h }
+func (h discardHandler) WithGroup(string) slog.Handler           { return h }
@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"runtime/pprof"
 	"time"
 
 	"github.com/AlexsanderHamir/ringbuffer"
@@ -15,8 +16,22 @@ var (
 	errNoItemsToMove    = errors.New("no items to move")
 	errNilObject        = errors.New("object is nil")
 	errNilConfig        = errors.New("config is nil")
+	errWaitFailed       = errors.New("failed waiting for an object")
+	errInvalidBatchSize = errors.New("invalid number of objects")
+	errAllocationFailed = errors.New("allocation failed")
+	errCallbackPanicked = errors.New("callback panicked")
+	errPoolClosed       = errors.New("pool is closed")
+	errWouldBlock       = errors.New("not enough idle objects and the ring buffer is not blocking")
 )
 
+// ErrOverloaded is returned when a caller would exceed the maximum number of waiters,
+// or waited longer than the queue-time budget.
+var ErrOverloaded = errors.New("pool is overloaded")
+
+// ErrCircuitOpen is returned when the allocator failed repeatedly and the pool stopped calling it
+// for a while, and there are no idle objects left to hand out.
+var ErrCircuitOpen = errors.New("allocation circuit is open")
+
 // NewPool creates a new object pool with the given configuration.
 //
 // The allocator function creates a new object and returns a pointer to it.
@@ -39,6 +54,9 @@ func NewPool[T any](config *PoolConfig[T], allocator func() T, cleaner func(T),
 		return nil, err
 	}
 
+	// the pool owns its configuration, Reconfigure modifies it
+	config = config.clone()
+
 	ringBuffer, err := ringbuffer.NewWithConfig(config.initialCapacity, config.ringBufferConfig)
 	if err != nil {
 		return nil, err
@@ -52,6 +70,7 @@ func NewPool[T any](config *PoolConfig[T], allocator func() T, cleaner func(T),
 	}
 
 	ringBuffer.WithPreReadBlockHook(poolObj.preReadBlockHook)
+	poolObj.boundReads(ringBuffer)
 
 	poolObj.ctx, poolObj.cancel = context.WithCancel(context.Background())
 
@@ -64,42 +83,213 @@ func NewPool[T any](config *PoolConfig[T], allocator func() T, cleaner func(T),
 
 	go poolObj.shrink()
 
+	if poolObj.replenishSignal != nil {
+		go poolObj.replenish()
+	}
+
+	if poolObj.history != nil {
+		poolObj.history.add(poolObj.GetPoolStatsSnapshot())
+		go poolObj.sampleStats()
+	}
+
 	return poolObj, nil
 }
 
 // Get returns an object from the pool, either from L1 cache or the ring buffer, preferring L1.
-func (p *Pool[T]) Get() (zero T, err error) {
-	if obj, found := p.tryGetFromL1(false); found {
-		return obj, nil
+func (p *Pool[T]) Get() (T, error) {
+	var (
+		obj  T
+		path GetPath
+		err  error
+	)
+
+	if p.getLatency == nil {
+		obj, _, err = p.get()
+	} else {
+		start := time.Now()
+		obj, path, err = p.get()
+		p.getLatency.observe(path, err, time.Since(start))
 	}
 
-	if obj, found := p.tryRefillAndFromGetL1(); found {
-		return obj, nil
+	if err != nil {
+		return obj, p.withName(err)
 	}
 
-	obj, err := p.SlowPathGet()
+	if p.outstanding != nil {
+		p.trackOutstanding(obj)
+	}
+
+	return obj, nil
+}
+
+// get is Get, it also reports the path the object was obtained through.
+func (p *Pool[T]) get() (zero T, path GetPath, err error) {
+	if p.queuesGetCallers() && p.waiters.len() > 0 {
+		obj, err := p.waitForObject(context.Background(), 0)
+		return obj, GetPathWaitQueue, err
+	}
+
+	if obj, found := p.getFromL1(); found {
+		return obj, GetPathL1Hit, nil
+	}
+
+	if err := p.errIfCircuitOpen(); err != nil {
+		return zero, GetPathFailed, err
+	}
+
+	if p.queuesGetCallers() {
+		return p.queuedGet(context.Background(), 0)
+	}
+
+	obj, path, found, err := p.tryRefillAndFromGetL1()
+	if err != nil {
+		return zero, GetPathFailed, err
+	}
+
+	if found {
+		return obj, path, nil
+	}
+
+	obj, blocked, err := p.slowPathGet()
 	if err != nil {
-		return zero, err
+		return zero, GetPathFailed, err
+	}
+
+	if blocked {
+		return obj, GetPathRingBufferBlocked, nil
+	}
+
+	return obj, GetPathRingBuffer, nil
+}
+
+// getFromL1 is the L1 fast path of Get and GetPriority, it wakes up the replenisher when L1 runs low.
+func (p *Pool[T]) getFromL1() (zero T, found bool) {
+	p.mu.RLock()
+	ch := *p.cacheL1
+	p.mu.RUnlock()
+
+	obj, found := p.receiveFromL1(ch)
+	if found {
+		p.stats.l1Hits.Add(1)
+	} else {
+		p.stats.l1Misses.Add(1)
+	}
+
+	if p.replenishSignal != nil {
+		p.nudgeReplenisher(ch)
+	}
+
+	return obj, found
+}
+
+// GetPriority retrieves an object from the pool like Get. When the pool is exhausted, the caller
+// is queued with the given priority. Priorities only order the callers in the wait queue: returned objects
+// are handed to queued callers highest priority first, and in FIFO order within the same priority.
+//
+// In fair mode every exhausted Get caller is queued with priority 0, so a negative priority yields to them.
+// Otherwise Get callers wait on a refill or block in the ring buffer outside the queue, and every queued
+// caller is served ahead of them whatever its priority, negative priorities included.
+//
+// The wait ends when an object is handed to the caller, ctx is done, the ring buffer read timeout
+// expires or the pool is closed. Nobody waits when the ring buffer isn't blocking, GetPriority then
+// fails like Get when no object is available and the priority has no effect.
+func (p *Pool[T]) GetPriority(ctx context.Context, priority int) (obj T, err error) {
+	if !p.config.profilerLabels {
+		return p.getPriorityAndTrack(ctx, priority)
+	}
+
+	pprof.Do(ctx, poolLabels(p.config.name, opGetPriority), func(ctx context.Context) {
+		obj, err = p.getPriorityAndTrack(ctx, priority)
+	})
+	return obj, err
+}
+
+// getPriorityAndTrack is GetPriority without the profiler labels.
+func (p *Pool[T]) getPriorityAndTrack(ctx context.Context, priority int) (T, error) {
+	var (
+		obj  T
+		path GetPath
+		err  error
+	)
+
+	if p.getLatency == nil {
+		obj, _, err = p.getPriority(ctx, priority)
+	} else {
+		start := time.Now()
+		obj, path, err = p.getPriority(ctx, priority)
+		p.getLatency.observe(path, err, time.Since(start))
+	}
+
+	if err != nil {
+		return obj, p.withName(err)
+	}
+
+	if p.outstanding != nil {
+		p.trackOutstanding(obj)
 	}
 
 	return obj, nil
 }
 
+// getPriority is GetPriority, without the pool name attached to the error.
+// It also reports the path the object was obtained through.
+func (p *Pool[T]) getPriority(ctx context.Context, priority int) (zero T, path GetPath, err error) {
+	if !p.usesWaitQueue() {
+		return p.get()
+	}
+
+	if p.waiters.len() > 0 {
+		obj, err := p.waitForObject(ctx, priority)
+		return obj, GetPathWaitQueue, err
+	}
+
+	if obj, found := p.getFromL1(); found {
+		return obj, GetPathL1Hit, nil
+	}
+
+	if err := p.errIfCircuitOpen(); err != nil {
+		return zero, GetPathFailed, err
+	}
+
+	return p.queuedGet(ctx, priority)
+}
+
 // Put returns an object to the pool. The object will be cleaned using the cleaner function
-// before being made available for reuse.
+// before being made available for reuse. If the cleaner panics, the object is discarded
+// instead of being reused and an error is returned.
 func (p *Pool[T]) Put(obj T) error {
+	if p.outstanding != nil {
+		p.outstanding.Remove(obj)
+	}
+
+	return p.withName(p.put(obj))
+}
+
+// put is Put, without the pool name attached to the error.
+func (p *Pool[T]) put(obj T) error {
 	defer func() {
 		p.refillCond.Signal()
 	}()
 
-	p.cleaner(obj)
+	if err := p.callCleaner(obj); err != nil {
+		p.discard(obj)
+		return fmt.Errorf("object discarded: %w", err)
+	}
+
+	if p.waiters.len() > 0 && p.handoff(obj) {
+		p.stats.waitQueueHandoffs.Add(1)
+		return nil
+	}
 
 	if p.tryFastPathPut(obj) {
 		p.pool.WakeUpOneReader()
+		p.serveWaitersIfAny()
 		return nil
 	}
 
-	return p.slowPathPut(obj)
+	err := p.slowPathPut(obj)
+	p.serveWaitersIfAny()
+	return err
 }
 
 // Close closes the pool and releases all resources. If there are outstanding objects,
@@ -113,11 +303,31 @@ func (p *Pool[T]) Close() error {
 	return nil
 }
 
+// Name returns the name of the pool set with SetName, empty if it has none.
+func (p *Pool[T]) Name() string {
+	return p.config.name
+}
+
+// withName prefixes err with the name of the pool, if it has one, so errors of different pools can be told apart.
+// The original error is wrapped, errors.Is keeps working.
+func (p *Pool[T]) withName(err error) error {
+	if err == nil || p.config.name == "" {
+		return err
+	}
+
+	return fmt.Errorf("pool %q: %w", p.config.name, err)
+}
+
 // shrink is a background goroutine that periodically checks the pool for idle and underutilized objects,
 // and shrinks the pool if necessary to free up memory.
 func (p *Pool[T]) shrink() {
-	params := p.config.shrink
-	ticker := time.NewTicker(params.checkInterval)
+	p.labelGoroutine(roleShrink)
+
+	p.mu.RLock()
+	interval := p.config.shrink.checkInterval
+	p.mu.RUnlock()
+
+	ticker := time.NewTicker(interval)
 	defer ticker.Stop()
 
 	var (
@@ -132,6 +342,13 @@ func (p *Pool[T]) shrink() {
 		case <-ticker.C:
 			p.mu.Lock()
 
+			// the parameters are replaced by Reconfigure
+			params := p.config.shrink
+			if params.checkInterval != interval {
+				interval = params.checkInterval
+				ticker.Reset(interval)
+			}
+
 			if p.handleMaxConsecutiveShrinks(params.maxConsecutiveShrinks) {
 				p.mu.Unlock()
 				continue
@@ -167,21 +384,33 @@ func (p *Pool[T]) shrink() {
 // grow is called when the demand for objects exceeds the current capacity, if enabled.
 // It increases the pool's capacity according to the growth configuration.
 func (p *Pool[T]) grow() error {
+	defer p.startOp(opGrow, opRefill).end()
+
 	defer func() {
 		p.shrinkCond.Signal()
 	}()
 
 	if p.isGrowthBlocked.Load() {
-		return errGrowthBlocked
+		return p.growthBlocked()
+	}
+
+	if p.isCircuitOpen() {
+		return ErrCircuitOpen
 	}
 
+	oldCapacity := p.stats.currentCapacity
 	newCapacity := p.calculateNewPoolCapacity()
 
 	if err := p.updatePoolCapacity(newCapacity); err != nil {
+		p.logger.Warn("growth failed", "capacity", oldCapacity, "new_capacity", newCapacity, "error", err)
 		return fmt.Errorf("%w: %w", errRingBufferFailed, err)
 	}
 
 	p.stats.totalGrowthEvents++
+	p.logger.Info("pool grew", "old_capacity", oldCapacity, "new_capacity", p.stats.currentCapacity)
+	p.listener.OnGrow(oldCapacity, p.stats.currentCapacity)
+	p.metrics.growthEvents.Add(1)
+	p.metrics.capacity.Set(float64(p.stats.currentCapacity))
 	err := p.tryL1ResizeIfTriggered()
 	if err != nil {
 		return err
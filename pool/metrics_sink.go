@@ -0,0 +1,124 @@
+package pool
+
+import "time"
+
+// Names of the metrics reported to a MetricsSink.
+const (
+	MetricGetsL1         = "gets.l1"          // counter, objects handed out from the L1 cache
+	MetricGetsRingBuffer = "gets.ring_buffer" // counter, objects handed out from the ring buffer
+	MetricGetsHandoff    = "gets.handoff"     // counter, objects handed directly to queued waiters
+	MetricGrowthEvents   = "growth_events"    // counter, ring buffer growth events
+	MetricShrinkEvents   = "shrink_events"    // counter, ring buffer shrink events
+	MetricAllocations    = "allocations"      // counter, objects created
+	MetricDestructions   = "destructions"     // counter, objects dropped
+	MetricCapacity       = "capacity"         // gauge, ring buffer capacity
+	MetricL1Capacity     = "l1_capacity"      // gauge, L1 cache capacity
+	MetricWaitDuration   = "wait_duration"    // histogram, seconds callers spent waiting for an object
+)
+
+// MetricsSink creates the metric handles a pool updates as events happen, registered through the
+// builder with SetMetricsSink. Handles are created once, when the pool is created, so the sink
+// isn't involved in the hot paths beyond the handle updates.
+//
+// Handles are updated from the goroutine that triggered the event, some of them while internal
+// locks are held. Updates must be fast and safe for concurrent use.
+type MetricsSink interface {
+	Counter(name string) Counter
+	Gauge(name string) Gauge
+	Histogram(name string) Histogram
+}
+
+// PoolScopedSink is implemented by sinks that tell the metrics of different pools apart themselves,
+// with a tag or an attribute for instance. The handles of a named pool are created from ForPool(name).
+// The handles of a named pool using a sink that doesn't implement it are created with the metric names
+// prefixed by the pool name and a dot, so pools sharing a sink never update the same series.
+type PoolScopedSink interface {
+	ForPool(name string) MetricsSink
+}
+
+// Counter is a monotonically increasing metric.
+type Counter interface {
+	Add(delta uint64)
+}
+
+// Gauge is a metric that can go up and down.
+type Gauge interface {
+	Set(value float64)
+}
+
+// Histogram records the distribution of observed values.
+type Histogram interface {
+	Observe(value float64)
+}
+
+// NopMetricsSink is a MetricsSink whose handles discard every update.
+type NopMetricsSink struct{}
+
+func (NopMetricsSink) Counter(name string) Counter     { return nopMetric{} }
+func (NopMetricsSink) Gauge(name string) Gauge         { return nopMetric{} }
+func (NopMetricsSink) Histogram(name string) Histogram { return nopMetric{} }
+
+type nopMetric struct{}
+
+func (nopMetric) Add(delta uint64)      {}
+func (nopMetric) Set(value float64)     {}
+func (nopMetric) Observe(value float64) {}
+
+// poolMetrics holds the handles the pool updates, resolved once from the sink.
+type poolMetrics struct {
+	getsL1         Counter
+	getsRingBuffer Counter
+	getsHandoff    Counter
+	growthEvents   Counter
+	shrinkEvents   Counter
+	allocations    Counter
+	destructions   Counter
+	capacity       Gauge
+	l1Capacity     Gauge
+	waitDuration   Histogram
+}
+
+// newPoolMetrics resolves the handles of the pool named name, using NopMetricsSink if sink is nil.
+func newPoolMetrics(sink MetricsSink, name string) *poolMetrics {
+	if sink == nil {
+		sink = NopMetricsSink{}
+	} else if name != "" {
+		sink = scopeSink(sink, name)
+	}
+
+	return &poolMetrics{
+		getsL1:         sink.Counter(MetricGetsL1),
+		getsRingBuffer: sink.Counter(MetricGetsRingBuffer),
+		getsHandoff:    sink.Counter(MetricGetsHandoff),
+		growthEvents:   sink.Counter(MetricGrowthEvents),
+		shrinkEvents:   sink.Counter(MetricShrinkEvents),
+		allocations:    sink.Counter(MetricAllocations),
+		destructions:   sink.Counter(MetricDestructions),
+		capacity:       sink.Gauge(MetricCapacity),
+		l1Capacity:     sink.Gauge(MetricL1Capacity),
+		waitDuration:   sink.Histogram(MetricWaitDuration),
+	}
+}
+
+// scopeSink returns the sink creating the handles of the pool named name, see PoolScopedSink.
+func scopeSink(sink MetricsSink, name string) MetricsSink {
+	if scoped, ok := sink.(PoolScopedSink); ok {
+		return scoped.ForPool(name)
+	}
+	return prefixedSink{sink: sink, prefix: name + "."}
+}
+
+// prefixedSink prefixes the names of the handles created by sink.
+type prefixedSink struct {
+	sink   MetricsSink
+	prefix string
+}
+
+func (s prefixedSink) Counter(name string) Counter     { return s.sink.Counter(s.prefix + name) }
+func (s prefixedSink) Gauge(name string) Gauge         { return s.sink.Gauge(s.prefix + name) }
+func (s prefixedSink) Histogram(name string) Histogram { return s.sink.Histogram(s.prefix + name) }
+
+// observeWait records the duration of a wait for an object.
+func (m *poolMetrics) observeWait(waited time.Duration) {
+	m.waitDuration.Observe(waited.Seconds())
+}
@@ -0,0 +1,40 @@
+package pool
+
+import (
+	"runtime/pprof"
+	"sync"
+)
+
+// outstandingProfilePrefix is the prefix of the names of the outstanding object profiles.
+const outstandingProfilePrefix = "poolx.outstanding."
+
+// outstandingProfilesMu serializes the lookup and creation of the outstanding object profiles.
+var outstandingProfilesMu sync.Mutex
+
+// outstandingProfile returns the profile recording the outstanding objects of the pool named name.
+// pprof profiles can't be unregistered, so a pool created with the name of a previous pool reuses its profile.
+func outstandingProfile(name string) *pprof.Profile {
+	outstandingProfilesMu.Lock()
+	defer outstandingProfilesMu.Unlock()
+
+	profileName := outstandingProfilePrefix + name
+	if profile := pprof.Lookup(profileName); profile != nil {
+		return profile
+	}
+
+	return pprof.NewProfile(profileName)
+}
+
+// trackOutstanding records the stack of the caller of the public Get method that handed out obj.
+// It's called directly by the Get methods, skip accounts for trackOutstanding and the Get method.
+func (p *Pool[T]) trackOutstanding(obj T) {
+	// an object returned twice by a caller bug can be handed out twice, Add panics on duplicates
+	p.outstanding.Remove(obj)
+	p.outstanding.Add(obj, 2)
+}
+
+// OutstandingProfile returns the pprof profile holding the stack of every Get whose object wasn't returned yet,
+// nil if it's disabled. It's registered as poolx.outstanding.<name>, so it's also served by net/http/pprof.
+func (p *Pool[T]) OutstandingProfile() *pprof.Profile {
+	return p.outstanding
+}
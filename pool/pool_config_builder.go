@@ -2,6 +2,7 @@ package pool
 
 import (
 	"fmt"
+	"log/slog"
 	"time"
 
 	config "github.com/AlexsanderHamir/ringbuffer/config"
@@ -35,13 +36,14 @@ func NewPoolConfigBuilder[T any]() PoolConfigBuilder[T] {
 
 	pgb := &poolConfigBuilder[T]{
 		config: &PoolConfig[T]{
-			initialCapacity:    defaultPoolCapacity,
-			hardLimit:          defaultHardLimit,
-			shrink:             &copiedShrink,
-			growth:             &copiedGrowth,
-			fastPath:           &copiedFastPath,
-			ringBufferConfig:   &copiedRingBufferConfig,
-			allocationStrategy: &copiedAllocationStrategy,
+			initialCapacity:            defaultPoolCapacity,
+			hardLimit:                  defaultHardLimit,
+			shrink:                     &copiedShrink,
+			growth:                     &copiedGrowth,
+			fastPath:                   &copiedFastPath,
+			ringBufferConfig:           &copiedRingBufferConfig,
+			allocationStrategy:         &copiedAllocationStrategy,
+			circuitBreakerOpenDuration: defaultCircuitBreakerOpenDuration,
 		},
 	}
 
@@ -259,6 +261,93 @@ func (b *poolConfigBuilder[T]) SetRingBufferWriteTimeout(d time.Duration) PoolCo
 	return b
 }
 
+// SetFairWaiting enables or disables fair waiting for exhausted pools.
+// When enabled, Get callers arriving while others wait queue behind them instead of taking an idle object.
+func (b *poolConfigBuilder[T]) SetFairWaiting(enable bool) PoolConfigBuilder[T] {
+	b.config.fairWaiting = enable
+	return b
+}
+
+// SetMaxWaiters sets the maximum number of callers that can wait for an object at the same time,
+// on a refill, in the ring buffer or in the wait queue.
+// Callers beyond the limit fail fast with ErrOverloaded, 0 disables the limit.
+func (b *poolConfigBuilder[T]) SetMaxWaiters(count int) PoolConfigBuilder[T] {
+	if count >= 0 {
+		b.config.maxWaiters = count
+	}
+	return b
+}
+
+// SetQueueTimeBudget sets how long a caller may wait for an object before failing with ErrOverloaded,
+// 0 disables the budget.
+func (b *poolConfigBuilder[T]) SetQueueTimeBudget(d time.Duration) PoolConfigBuilder[T] {
+	if d >= 0 {
+		b.config.queueTimeBudget = d
+	}
+	return b
+}
+
+// SetPanicHandler sets the function called with every panic recovered from a user callback.
+func (b *poolConfigBuilder[T]) SetPanicHandler(handler func(CallbackPanic)) PoolConfigBuilder[T] {
+	b.config.panicHandler = handler
+	return b
+}
+
+// SetListener sets the listener notified of the pool's lifecycle events.
+func (b *poolConfigBuilder[T]) SetListener(listener Listener) PoolConfigBuilder[T] {
+	b.config.listener = listener
+	return b
+}
+
+// SetMetricsSink sets the sink whose metric handles the pool updates as events happen.
+func (b *poolConfigBuilder[T]) SetMetricsSink(sink MetricsSink) PoolConfigBuilder[T] {
+	b.config.metricsSink = sink
+	return b
+}
+
+// SetGetLatencyHistograms enables or disables the Get latency histograms.
+func (b *poolConfigBuilder[T]) SetGetLatencyHistograms(enable bool) PoolConfigBuilder[T] {
+	b.config.getLatencyHistograms = enable
+	return b
+}
+
+// SetProfilerLabels enables or disables the pprof labels of the background goroutines, GetPriority and GetAtomic.
+func (b *poolConfigBuilder[T]) SetProfilerLabels(enable bool) PoolConfigBuilder[T] {
+	b.config.profilerLabels = enable
+	return b
+}
+
+// SetOutstandingProfile enables or disables the pprof profile of the outstanding objects.
+func (b *poolConfigBuilder[T]) SetOutstandingProfile(enable bool) PoolConfigBuilder[T] {
+	b.config.outstandingProfile = enable
+	return b
+}
+
+// SetName sets the name identifying the pool in logs, errors and registries.
+func (b *poolConfigBuilder[T]) SetName(name string) PoolConfigBuilder[T] {
+	b.config.name = name
+	return b
+}
+
+// SetLogger sets the logger receiving the pool's internal diagnostics.
+func (b *poolConfigBuilder[T]) SetLogger(logger *slog.Logger) PoolConfigBuilder[T] {
+	b.config.logger = logger
+	return b
+}
+
+// SetSlabAllocation enables or disables slab allocation of pooled objects.
+// When enabled, AllocAmount becomes the number of objects per slab.
+func (b *poolConfigBuilder[T]) SetSlabAllocation(enable bool) PoolConfigBuilder[T] {
+	b.config.allocationStrategy.SlabAllocation = enable
+	return b
+}
+
+// SetSlabInitializer sets the function initializing slab members in place.
+func (b *poolConfigBuilder[T]) SetSlabInitializer(initialize func(T)) PoolConfigBuilder[T] {
+	b.config.slabInitializer = initialize
+	return b
+}
+
 // Build creates a new pool configuration with the configured settings.
 // It validates all configuration parameters and returns an error if any validation fails.
 // Returns a fully configured and validated PoolConfig instance.
@@ -228,6 +228,55 @@ func (b *poolConfigBuilder[T]) SetFastPathShrinkConfigs(shrinkPercent, minCapaci
 	return b
 }
 
+// SetFastPathBackgroundReplenisher enables or disables the background replenisher of the fast path.
+// Parameters:
+//   - enable: Whether the background replenisher should run
+//   - interval: How often the replenisher checks the L1 occupancy when no Get wakes it up
+//
+// Note: Zero or negative intervals are ignored, default values will be used instead.
+func (b *poolConfigBuilder[T]) SetFastPathBackgroundReplenisher(enable bool, interval time.Duration) PoolConfigBuilder[T] {
+	b.config.fastPath.backgroundReplenish = enable
+
+	if interval > 0 {
+		b.config.fastPath.replenishInterval = interval
+	}
+
+	return b
+}
+
+// SetCircuitBreaker configures the circuit breaker around the allocator.
+// Parameters:
+//   - failureThreshold: Consecutive allocation failures that open the circuit
+//   - openDuration: How long the circuit stays open before a probe allocation is attempted
+//
+// Note: Zero or negative values are ignored, default values will be used instead.
+func (b *poolConfigBuilder[T]) SetCircuitBreaker(failureThreshold int, openDuration time.Duration) PoolConfigBuilder[T] {
+	if failureThreshold > 0 {
+		b.config.circuitBreakerThreshold = failureThreshold
+	}
+
+	if openDuration > 0 {
+		b.config.circuitBreakerOpenDuration = openDuration
+	}
+
+	return b
+}
+
+// SetStatsHistory enables the background stats sampler.
+// Parameters:
+//   - interval: How often a snapshot of the statistics is recorded
+//   - size: How many snapshots are kept, older ones are dropped
+//
+// Note: The history is enabled only when both values are positive.
+func (b *poolConfigBuilder[T]) SetStatsHistory(interval time.Duration, size int) PoolConfigBuilder[T] {
+	if interval > 0 && size > 0 {
+		b.config.statsHistoryInterval = interval
+		b.config.statsHistorySize = size
+	}
+
+	return b
+}
+
 // SetFastPathShrinkAggressiveness sets the shrink aggressiveness level for the fast path.
 // Uses the same aggressiveness levels as the main pool (1-5).
 // Panics if:
@@ -0,0 +1,132 @@
+package pool
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Reconfigurable is implemented by pools whose configuration can be changed while they run,
+// *Pool[T] implements it for every T.
+type Reconfigurable interface {
+	// GetConfigSpec returns the serializable view of the pool configuration.
+	GetConfigSpec() ConfigSpec
+	// Reconfigure applies spec to the running pool.
+	Reconfigure(spec ConfigSpec) error
+}
+
+// reconfigurableFields are the JSON names of the parameters Reconfigure can change.
+var reconfigurableFields = []string{"hard_limit", "growth", "shrink", "fast_path.shrink"}
+
+// Reconfigure applies spec to the running pool. The parameters that size the pool can be changed:
+// the hard limit, the growth parameters and the shrink parameters of the ring buffer and the L1 cache.
+// The others are fixed when the pool is created, spec must keep their current values, see GetConfigSpec.
+//
+// spec is validated by the same rules as Build. When it's invalid or changes a fixed parameter, an error
+// is returned and the pool is left untouched. Otherwise the new values are used from the next growth or
+// shrink check on. Lowering the hard limit below the current capacity blocks growth until the pool shrinks.
+func (p *Pool[T]) Reconfigure(spec ConfigSpec) error {
+	if err := checkReconfigurable(p.GetConfigSpec(), spec); err != nil {
+		return p.withName(err)
+	}
+
+	config, err := NewPoolConfigFromSpec[T](spec)
+	if err != nil {
+		return p.withName(fmt.Errorf("invalid configuration: %w", err))
+	}
+
+	if p.ctx.Err() != nil {
+		return p.withName(errPoolClosed)
+	}
+
+	// growth runs under the refill semaphore and shrinking under the pool lock, the same order as the refill path
+	select {
+	case p.refillSemaphore <- struct{}{}:
+		defer p.releaseRefill()
+	case <-p.ctx.Done():
+		return p.withName(errPoolClosed)
+	}
+
+	p.mu.Lock()
+	p.config.hardLimit = config.hardLimit
+	p.config.growth = config.growth
+	p.config.shrink = config.shrink
+	p.config.fastPath.shrink = config.fastPath.shrink
+	p.isGrowthBlocked.Store(p.stats.currentCapacity >= config.hardLimit)
+	p.mu.Unlock()
+
+	p.logger.Info("pool reconfigured", "hard_limit", config.hardLimit, "shrink_aggressiveness", config.shrink.aggressivenessLevel.String())
+	return nil
+}
+
+// checkReconfigurable returns an error naming the parameters spec changes that Reconfigure can't change.
+func checkReconfigurable(current, spec ConfigSpec) error {
+	spec.HardLimit = current.HardLimit
+	spec.Growth = current.Growth
+	spec.Shrink = current.Shrink
+	spec.FastPath.Shrink = current.FastPath.Shrink
+	if spec == current {
+		return nil
+	}
+
+	changed := "parameters"
+	if fields := changedFields(current, spec); len(fields) > 0 {
+		changed = strings.Join(fields, ", ")
+	}
+
+	return fmt.Errorf("%s can't be changed on a running pool, only %s can", changed, strings.Join(reconfigurableFields, ", "))
+}
+
+// changedFields returns the JSON names of the fields that differ between a and b, nested fields joined by dots.
+func changedFields(a, b ConfigSpec) []string {
+	var aFields, bFields map[string]any
+	if !decodeFields(a, &aFields) || !decodeFields(b, &bFields) {
+		return nil
+	}
+
+	var fields []string
+	diffFields("", aFields, bFields, &fields)
+	sort.Strings(fields)
+	return fields
+}
+
+func decodeFields(spec ConfigSpec, fields *map[string]any) bool {
+	data, err := json.Marshal(spec)
+	return err == nil && json.Unmarshal(data, fields) == nil
+}
+
+func diffFields(prefix string, a, b map[string]any, fields *[]string) {
+	for name, av := range a {
+		bv := b[name]
+
+		aMap, aIsMap := av.(map[string]any)
+		bMap, bIsMap := bv.(map[string]any)
+		if aIsMap && bIsMap {
+			diffFields(prefix+name+".", aMap, bMap, fields)
+			continue
+		}
+
+		if fmt.Sprint(av) != fmt.Sprint(bv) {
+			*fields = append(*fields, prefix+name)
+		}
+	}
+
+	for name := range b {
+		if _, ok := a[name]; !ok {
+			*fields = append(*fields, prefix+name)
+		}
+	}
+}
+
+// clone returns a copy of the configuration, so that Reconfigure doesn't modify the configuration the pool
+// was created with. The parameter structs are shared, Reconfigure replaces them instead of modifying them.
+func (c *PoolConfig[T]) clone() *PoolConfig[T] {
+	copied := *c
+	if c.fastPath != nil {
+		fastPath := *c.fastPath
+		copied.fastPath = &fastPath
+	}
+
+	return &copied
+}
@@ -0,0 +1,217 @@
+package pool
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"regexp"
+	"sync"
+	"syscall"
+	"time"
+)
+
+// ManagedPool is the view of a pool kept by a Registry, *Pool[T] implements it for every T.
+type ManagedPool interface {
+	// Name returns the name the pool was built with.
+	Name() string
+	// Close releases all resources associated with the pool.
+	Close() error
+	// GetPoolStatsSnapshot returns a snapshot of the pool statistics.
+	GetPoolStatsSnapshot() *PoolStatsSnapshot
+	// GetExtendedStatsSnapshot returns a snapshot of the pool statistics, including the Get latency histograms.
+	GetExtendedStatsSnapshot() *ExtendedPoolStatsSnapshot
+	// GetConfigSpec returns the serializable view of the pool configuration.
+	GetConfigSpec() ConfigSpec
+	// WriteStats writes the current pool statistics to w in the given format.
+	WriteStats(w io.Writer, format Format) error
+}
+
+// RegisteredPool is a pool kept by a Registry, along with its labels.
+type RegisteredPool struct {
+	Name   string
+	Labels map[string]string
+	Pool   ManagedPool
+}
+
+// labelName matches valid label names, the same rules as Prometheus label names.
+var labelName = regexp.MustCompile(`^[a-zA-Z_][a-zA-Z0-9_]*$`)
+
+// Registry keeps track of the pools of a process by name, so they can be enumerated, looked up,
+// exported and closed together on shutdown.
+//
+// The registry uses the name the pool was built with (see SetName), so the same name shows up
+// in the pool logs, in the errors returned by the pool and in the exported metrics.
+type Registry struct {
+	mu    sync.RWMutex
+	pools map[string]*RegisteredPool
+	order []string
+}
+
+// NewRegistry creates an empty registry.
+func NewRegistry() *Registry {
+	return &Registry{pools: make(map[string]*RegisteredPool)}
+}
+
+// Register adds a pool to the registry under its name, with optional labels.
+// Returns an error if the pool is nil, has no name, the name is already taken, or a label name is invalid.
+// Label names follow the Prometheus rules and "pool" is reserved for the name.
+func (r *Registry) Register(p ManagedPool, labels map[string]string) error {
+	if p == nil {
+		return fmt.Errorf("cannot register a nil pool")
+	}
+
+	name := p.Name()
+	if name == "" {
+		return fmt.Errorf("cannot register a pool without a name, set one with SetName")
+	}
+
+	copied := make(map[string]string, len(labels))
+	for k, v := range labels {
+		if !labelName.MatchString(k) || k == "pool" {
+			return fmt.Errorf("pool %q: invalid label name %q", name, k)
+		}
+		copied[k] = v
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.pools[name]; ok {
+		return fmt.Errorf("pool %q is already registered", name)
+	}
+
+	r.pools[name] = &RegisteredPool{Name: name, Labels: copied, Pool: p}
+	r.order = append(r.order, name)
+	return nil
+}
+
+// Unregister removes the pool registered under name without closing it.
+// Returns false if no pool is registered under name.
+func (r *Registry) Unregister(name string) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.pools[name]; !ok {
+		return false
+	}
+
+	delete(r.pools, name)
+	for i, n := range r.order {
+		if n == name {
+			r.order = append(r.order[:i], r.order[i+1:]...)
+			break
+		}
+	}
+
+	return true
+}
+
+// Lookup returns the pool registered under name.
+func (r *Registry) Lookup(name string) (RegisteredPool, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	entry, ok := r.pools[name]
+	if !ok {
+		return RegisteredPool{}, false
+	}
+
+	return *entry, true
+}
+
+// Pools returns the registered pools in registration order.
+func (r *Registry) Pools() []RegisteredPool {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	pools := make([]RegisteredPool, len(r.order))
+	for i, name := range r.order {
+		pools[i] = *r.pools[name]
+	}
+
+	return pools
+}
+
+// LookupPool returns the pool of type T registered under name.
+// Returns false if no pool is registered under name or it holds objects of a different type.
+func LookupPool[T any](r *Registry, name string) (*Pool[T], bool) {
+	entry, ok := r.Lookup(name)
+	if !ok {
+		return nil, false
+	}
+
+	p, ok := entry.Pool.(*Pool[T])
+	return p, ok
+}
+
+// CloseAll closes and unregisters every pool in reverse registration order, so pools registered
+// later, which may depend on pools registered earlier, are closed first.
+//
+// Close waits for outstanding objects to be returned, CloseAll gives up when ctx is done:
+// the pool being closed is left closing in the background, the remaining pools are left open and registered,
+// and the returned error wraps ctx.Err() and names them. Errors returned by Close are joined, prefixed by the pool name.
+func (r *Registry) CloseAll(ctx context.Context) error {
+	pools := r.Pools()
+
+	var errs []error
+	for i := len(pools) - 1; i >= 0; i-- {
+		entry := pools[i]
+		if ctx.Err() != nil {
+			return errors.Join(append(errs, notClosed(pools[:i+1], ctx.Err()))...)
+		}
+
+		done := make(chan error, 1)
+		go func() {
+			done <- entry.Pool.Close()
+		}()
+
+		select {
+		case err := <-done:
+			r.Unregister(entry.Name)
+			if err != nil {
+				errs = append(errs, fmt.Errorf("pool %q: %w", entry.Name, err))
+			}
+		case <-ctx.Done():
+			return errors.Join(append(errs, notClosed(pools[:i+1], ctx.Err()))...)
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// notClosed reports the pools CloseAll gave up on, in the order they would have been closed.
+func notClosed(pools []RegisteredPool, cause error) error {
+	names := make([]string, 0, len(pools))
+	for i := len(pools) - 1; i >= 0; i-- {
+		names = append(names, pools[i].Name)
+	}
+
+	return fmt.Errorf("pools %q not closed: %w", names, cause)
+}
+
+// CloseOnSignal blocks until one of the signals is received or ctx is done, then closes every pool with CloseAll,
+// giving them up to timeout to return their outstanding objects. It listens for os.Interrupt and SIGTERM
+// when no signals are given. It's meant to be run by the main goroutine, or in its own goroutine, on startup:
+//
+//	go func() {
+//		if err := registry.CloseOnSignal(ctx, 5*time.Second); err != nil {
+//			log.Print(err)
+//		}
+//	}()
+func (r *Registry) CloseOnSignal(ctx context.Context, timeout time.Duration, signals ...os.Signal) error {
+	if len(signals) == 0 {
+		signals = []os.Signal{os.Interrupt, syscall.SIGTERM}
+	}
+
+	notifyCtx, stop := signal.NotifyContext(ctx, signals...)
+	<-notifyCtx.Done()
+	stop()
+
+	closeCtx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	return r.CloseAll(closeCtx)
+}
@@ -0,0 +1,89 @@
+package pool
+
+import "time"
+
+// replenish is a background goroutine that keeps the L1 cache stocked off the Get path.
+// It wakes up when a Get notices that L1 dropped below the refill threshold, and periodically
+// as a safety net, so that callers on the L1 hit path never pay the refill latency.
+func (p *Pool[T]) replenish() {
+	p.labelGoroutine(roleReplenish)
+
+	ticker := time.NewTicker(p.config.fastPath.replenishInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-p.ctx.Done():
+			return
+		case <-p.replenishSignal:
+		case <-ticker.C:
+		}
+
+		p.replenishL1()
+	}
+}
+
+// replenishL1 refills or allocates objects into L1 if its occupancy dropped below refillPercent.
+// It shares the refill semaphore with the inline refill path, so if a Get is already refilling
+// there is nothing left to do.
+func (p *Pool[T]) replenishL1() {
+	select {
+	case p.refillSemaphore <- struct{}{}:
+	default:
+		return
+	}
+
+	defer p.releaseRefill()
+
+	p.mu.RLock()
+	currentCap, _ := p.calculateL1Usage()
+	fillTarget := p.calculateFillTarget(currentCap)
+	belowThreshold := p.isL1BelowRefillThreshold()
+	p.mu.RUnlock()
+
+	if !belowThreshold || fillTarget == 0 {
+		return
+	}
+
+	p.stats.replenishRuns.Add(1)
+
+	p.mu.Lock()
+	created := p.createIfSpaceAvailable(fillTarget)
+	p.mu.Unlock()
+
+	if created {
+		return
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if _, err := p.tryRefill(fillTarget); err != nil {
+		p.logger.Debug("background replenish failed", "fill_target", fillTarget, "error", err)
+	}
+}
+
+// nudgeReplenisher wakes up the background replenisher if ch, the L1 channel the caller got an object from,
+// dropped below the refill threshold. It's called on every Get and takes no lock, it never blocks,
+// if the replenisher already has a pending wake up the call is a no-op.
+func (p *Pool[T]) nudgeReplenisher(ch chan T) {
+	if !isBelowRefillThreshold(ch, p.config.fastPath.refillPercent) {
+		return
+	}
+
+	select {
+	case p.replenishSignal <- struct{}{}:
+	default:
+	}
+}
+
+// isL1BelowRefillThreshold reports whether the L1 occupancy is below refillPercent of its capacity.
+// The caller must hold at least the read lock.
+func (p *Pool[T]) isL1BelowRefillThreshold() bool {
+	return isBelowRefillThreshold(*p.cacheL1, p.config.fastPath.refillPercent)
+}
+
+// isBelowRefillThreshold reports whether the occupancy of the L1 channel ch is below refillPercent of its capacity.
+func isBelowRefillThreshold[T any](ch chan T, refillPercent int) bool {
+	return len(ch)*100 < cap(ch)*refillPercent
+}
@@ -0,0 +1,151 @@
+package pool
+
+import (
+	"fmt"
+	"reflect"
+	"sync"
+)
+
+// slab is a contiguous block of pooled objects. The pool keeps a reference to the
+// backing array until every member of the slab has been destroyed.
+type slab struct {
+	// block is the backing []E, it keeps the whole allocation alive.
+	block reflect.Value
+
+	// live is the number of members that haven't been destroyed yet.
+	live int
+}
+
+// slabAllocator allocates pooled objects in contiguous slabs and keeps the
+// bookkeeping required to release a slab once all of its members are destroyed.
+type slabAllocator[T any] struct {
+	mu sync.Mutex
+
+	// elem is the element type E of the slab, where T is *E.
+	elem reflect.Type
+
+	// owners maps the address of each live member to the slab that holds it.
+	owners map[uintptr]*slab
+
+	activeSlabs   int
+	releasedSlabs int
+}
+
+// newSlabAllocator creates a slab allocator for the pointer type T.
+func newSlabAllocator[T any]() (*slabAllocator[T], error) {
+	var zero T
+	typ := reflect.TypeOf(zero)
+	if typ == nil || typ.Kind() != reflect.Ptr {
+		return nil, fmt.Errorf("slab allocation requires a pointer type, got %T", zero)
+	}
+
+	return &slabAllocator[T]{
+		elem:   typ.Elem(),
+		owners: make(map[uintptr]*slab),
+	}, nil
+}
+
+// allocate creates a single slab of n objects. Members start as the zero value of E and are
+// initialized in place by initialize, nothing is copied into the slab. When initialize fails,
+// the members initialized so far are returned along with the error.
+func (s *slabAllocator[T]) allocate(n int, initialize func(T) error) ([]T, error) {
+	if n <= 0 {
+		return nil, nil
+	}
+
+	block := reflect.MakeSlice(reflect.SliceOf(s.elem), n, n)
+	objs := make([]T, 0, n)
+
+	var err error
+	for i := range n {
+		obj := block.Index(i).Addr().Interface().(T)
+		if err = initialize(obj); err != nil {
+			break
+		}
+		objs = append(objs, obj)
+	}
+
+	if len(objs) == 0 {
+		return nil, err
+	}
+
+	sl := &slab{block: block, live: len(objs)}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, obj := range objs {
+		s.owners[reflect.ValueOf(obj).Pointer()] = sl
+	}
+
+	s.activeSlabs++
+	return objs, err
+}
+
+// release marks obj as destroyed, dropping its slab once no members are left.
+// Objects that weren't allocated from a slab are ignored.
+func (s *slabAllocator[T]) release(obj T) {
+	addr := reflect.ValueOf(obj).Pointer()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	sl, ok := s.owners[addr]
+	if !ok {
+		return
+	}
+
+	delete(s.owners, addr)
+	sl.live--
+	if sl.live == 0 {
+		sl.block = reflect.Value{}
+		s.activeSlabs--
+		s.releasedSlabs++
+	}
+}
+
+// reset drops every slab, used when the pool is closed.
+func (s *slabAllocator[T]) reset() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.releasedSlabs += s.activeSlabs
+	s.activeSlabs = 0
+	s.owners = make(map[uintptr]*slab)
+}
+
+// counts returns the number of slabs currently held and the number of slabs released so far.
+func (s *slabAllocator[T]) counts() (active, released int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.activeSlabs, s.releasedSlabs
+}
+
+// releaseObjects releases the slab membership of every destroyed object.
+func (p *Pool[T]) releaseObjects(objs []T) {
+	if p.slabs == nil {
+		return
+	}
+
+	for _, obj := range objs {
+		p.slabs.release(obj)
+	}
+}
+
+// initSlabMember runs the slab initializer on obj, if one is set, turning a panic into an error.
+func (p *Pool[T]) initSlabMember(obj T) (err error) {
+	initialize := p.config.slabInitializer
+	if initialize == nil {
+		return nil
+	}
+
+	defer func() {
+		if event := p.recoverCallback(CallbackSlabInitializer, recover()); event != nil {
+			err = fmt.Errorf("%w: %w: %s", errAllocationFailed, errCallbackPanicked, event)
+		}
+	}()
+
+	initialize(obj)
+	return nil
+}
@@ -2,6 +2,8 @@ package pool
 
 import (
 	"fmt"
+	"os"
+	"runtime"
 	"sync"
 	"sync/atomic"
 	"time"
@@ -23,6 +25,9 @@ type poolStats struct {
 	FastReturnHit  atomic.Uint64
 	FastReturnMiss atomic.Uint64
 
+	// waitQueueHandoffs counts the objects Put handed directly to a queued caller
+	waitQueueHandoffs atomic.Uint64
+
 	totalShrinkEvents  int
 	consecutiveShrinks int
 
@@ -31,69 +36,210 @@ type poolStats struct {
 	lastL1ResizeAtGrowthNum int
 	lastResizeAtShrinkNum   int
 	currentL1Capacity       int
+
+	// Background replenisher stats
+	replenishRuns atomic.Uint64
+
+	// Admission control stats
+	overloaded atomic.Uint64
+
+	// Callback stats
+	callbackPanics atomic.Uint64
+	discarded      atomic.Uint64
+
+	// Get path stats
+	l1Hits              atomic.Uint64
+	l1Misses            atomic.Uint64
+	refillAttempts      atomic.Uint64
+	refillSuccesses     atomic.Uint64
+	refillFailures      atomic.Uint64
+	refillGrowthBlocked atomic.Uint64
+	onDemandCreations   atomic.Uint64
+	slowPathGets        atomic.Uint64
+	slowPathRetries     atomic.Uint64
+}
+
+// totalReturns returns the number of objects given back to the pool, including the ones
+// handed directly to queued callers and the ones discarded because the cleaner panicked.
+func (s *poolStats) totalReturns() uint64 {
+	return s.FastReturnHit.Load() + s.FastReturnMiss.Load() + s.waitQueueHandoffs.Load() + s.discarded.Load()
 }
 
 // PoolStatsSnapshot represents a snapshot of the pool's statistics at a given moment
 type PoolStatsSnapshot struct {
+	// Timestamp is when the snapshot was taken
+	Timestamp time.Time `json:"timestamp"`
+
 	// Basic Pool Stats
-	InitialCapacity   int
-	CurrentCapacity   int
-	ObjectsInUse      uint64
-	TotalGets         uint64
-	TotalGrowthEvents int
-	ObjectsCreated    int
-	ObjectsDestroyed  int
+	InitialCapacity   int    `json:"initial_capacity"`
+	CurrentCapacity   int    `json:"current_capacity"`
+	ObjectsInUse      uint64 `json:"objects_in_use"`
+	TotalGets         uint64 `json:"total_gets"`
+	TotalGrowthEvents int    `json:"total_growth_events"`
+	ObjectsCreated    int    `json:"objects_created"`
+	ObjectsDestroyed  int    `json:"objects_destroyed"`
 
 	// Fast Return Stats
-	FastReturnHit  uint64
-	FastReturnMiss uint64
+	FastReturnHit  uint64 `json:"fast_return_hit"`
+	FastReturnMiss uint64 `json:"fast_return_miss"`
 
 	// Shrink Stats
-	TotalShrinkEvents  int
-	ConsecutiveShrinks int
-	LastShrinkTime     time.Time
+	TotalShrinkEvents  int       `json:"total_shrink_events"`
+	ConsecutiveShrinks int       `json:"consecutive_shrinks"`
+	LastShrinkTime     time.Time `json:"last_shrink_time"`
 
 	// L1 Cache Stats
-	LastL1ResizeAtGrowthNum int
-	LastResizeAtShrinkNum   int
-	CurrentL1Capacity       int
+	LastL1ResizeAtGrowthNum int `json:"last_l1_resize_at_growth_num"`
+	LastResizeAtShrinkNum   int `json:"last_resize_at_shrink_num"`
+	CurrentL1Capacity       int `json:"current_l1_capacity"`
 
 	// Derived Stats (computed from other fields)
-	AvailableObjects int
-	RingBufferLength int
-	L1Length         int
-	L2SpillRate      float64
-	Utilization      float64
+	AvailableObjects int     `json:"available_objects"`
+	RingBufferLength int     `json:"ring_buffer_length"`
+	L1Length         int     `json:"l1_length"`
+	L2SpillRate      float64 `json:"l2_spill_rate"`
+	Utilization      float64 `json:"utilization"`
+
+	// Background Replenisher Stats
+	ReplenishRuns uint64 `json:"replenish_runs"` // times the replenisher restocked L1
+
+	// Wait Queue Stats (fair mode)
+	Waiters           int           `json:"waiters"`             // callers currently queued
+	TotalWaits        uint64        `json:"total_waits"`         // callers that went through the queue
+	TotalWaitTime     time.Duration `json:"total_wait_time"`     // time spent in the queue by all callers
+	MaxWaitTime       time.Duration `json:"max_wait_time"`       // longest time a caller spent in the queue
+	Reserved          int           `json:"reserved"`            // returned objects held for callers of GetAtomic still waiting for the rest
+	WaitQueueHandoffs uint64        `json:"wait_queue_handoffs"` // returned objects handed directly to a queued caller
+
+	// Admission Control Stats
+	BlockedReaders int    `json:"blocked_readers"` // callers currently blocked reading the ring buffer
+	WaitingCallers int    `json:"waiting_callers"` // callers currently waiting for an object
+	Overloaded     uint64 `json:"overloaded"`      // callers rejected by the waiter cap or that exceeded the queue-time budget
+
+	// Circuit Breaker Stats (only populated when the circuit breaker is enabled)
+	CircuitState       string `json:"circuit_state"`       // closed, open or half-open
+	AllocationFailures int    `json:"allocation_failures"` // allocations that panicked or returned nil
+	CircuitTrips       int    `json:"circuit_trips"`       // times the circuit opened
+
+	// Callback Stats
+	CallbackPanics   uint64 `json:"callback_panics"`   // panics recovered from the allocator, cleaner, cloneTemplate or slab initializer
+	DiscardedObjects uint64 `json:"discarded_objects"` // objects dropped because the cleaner panicked on them
+
+	// Slab Stats (only populated when slab allocation is enabled)
+	ActiveSlabs   int `json:"active_slabs"`
+	ReleasedSlabs int `json:"released_slabs"`
+
+	// Get Path Stats
+	L1Hits              uint64 `json:"l1_hits"`               // Gets served by L1 on the first try
+	L1Misses            uint64 `json:"l1_misses"`             // Gets that found L1 empty, the ones the replenisher didn't prevent when it's enabled
+	RefillAttempts      uint64 `json:"refill_attempts"`       // refills of L1 from the ring buffer
+	RefillSuccesses     uint64 `json:"refill_successes"`      // refills that moved objects to L1
+	RefillFailures      uint64 `json:"refill_failures"`       // refills that failed, including the ones blocked by the hard limit
+	RefillGrowthBlocked uint64 `json:"refill_growth_blocked"` // refills that failed because growth was blocked by the hard limit
+	OnDemandCreations   uint64 `json:"on_demand_creations"`   // objects created on demand when L1 ran empty
+	SlowPathGets        uint64 `json:"slow_path_gets"`        // Gets that fell back to reading the ring buffer
+	SlowPathRetries     uint64 `json:"slow_path_retries"`     // ring buffer reads retried by the slow path
 }
 
 // PrintPoolStats prints the current statistics of the pool to stdout.
 // This includes information about pool capacity, object usage, hit rates,
 // and performance metrics.
 func (p *Pool[T]) PrintPoolStats() {
-	stats := p.GetPoolStatsSnapshot()
-	fmt.Printf("\n=== Pool Statistics ===\n")
-	fmt.Printf("Objects in use: %d\n", stats.ObjectsInUse)
-	fmt.Printf("Objects created: %d\n", stats.ObjectsCreated)
-	fmt.Printf("Objects destroyed: %d\n", stats.ObjectsDestroyed)
-	fmt.Printf("Available objects: %d\n", stats.AvailableObjects)
-	fmt.Printf("Current capacity: %d\n", stats.CurrentCapacity)
-	fmt.Printf("Ring buffer length: %d\n", stats.RingBufferLength)
-	fmt.Printf("Total gets: %d\n", stats.TotalGets)
-	fmt.Printf("Total growth events: %d\n", stats.TotalGrowthEvents)
-	fmt.Printf("Total shrink events: %d\n", stats.TotalShrinkEvents)
-	fmt.Printf("Consecutive shrinks: %d\n", stats.ConsecutiveShrinks)
-	fmt.Printf("L1 cache capacity: %d\n", stats.CurrentL1Capacity)
-	fmt.Printf("L1 cache length: %d\n", stats.L1Length)
-	fmt.Printf("Fast return hit: %d\n", stats.FastReturnHit)
-	fmt.Printf("Fast return miss: %d\n", stats.FastReturnMiss)
-	fmt.Printf("L2 spill rate: %.2f%%\n", stats.L2SpillRate*100)
-	fmt.Printf("Utilization: %.2f%%\n", stats.Utilization)
-	fmt.Printf("Last shrink time: %v\n", stats.LastShrinkTime)
-	fmt.Println("===================")
-}
-
-// GetPoolStatsSnapshot returns a snapshot of the current pool statistics
+	fmt.Println()
+	_ = p.WriteStats(os.Stdout, FormatText)
+}
+
+// SnapshotMode selects how GetStatsSnapshot reads the statistics of the pool.
+type SnapshotMode int
+
+const (
+	// SnapshotFast reads the statistics under the read lock of the pool, without pausing Get and Put calls.
+	// Counters updated while the snapshot is taken may be read at slightly different moments, so derived values
+	// such as ObjectsInUse can be off by the operations in flight.
+	SnapshotFast SnapshotMode = iota
+
+	// SnapshotConsistent reads the statistics under the write lock of the pool, which holds off refills, growth,
+	// shrinking and the Get and Put calls that take the lock, retrying until the Get and return counters read before
+	// and after the snapshot agree, so that they describe the same moment. It briefly stalls the pool, it's meant for
+	// periodic sampling rather than hot paths.
+	SnapshotConsistent
+)
+
+// String returns the name of the mode.
+func (m SnapshotMode) String() string {
+	switch m {
+	case SnapshotFast:
+		return "fast"
+	case SnapshotConsistent:
+		return "consistent"
+	default:
+		return fmt.Sprintf("SnapshotMode(%d)", int(m))
+	}
+}
+
+// maxConsistentSnapshotAttempts bounds the retries of a consistent snapshot under heavy traffic,
+// the last attempt is returned when the counters never settle.
+const maxConsistentSnapshotAttempts = 100
+
+// GetPoolStatsSnapshot returns a snapshot of the current pool statistics, taken in SnapshotFast mode.
 func (p *Pool[T]) GetPoolStatsSnapshot() *PoolStatsSnapshot {
+	return p.snapshot()
+}
+
+// GetStatsSnapshot returns a snapshot of the current pool statistics, taken in the given mode.
+func (p *Pool[T]) GetStatsSnapshot(mode SnapshotMode) *PoolStatsSnapshot {
+	if mode == SnapshotConsistent {
+		return p.consistentSnapshot()
+	}
+
+	return p.snapshot()
+}
+
+// counterCut holds the counters ObjectsInUse is derived from.
+type counterCut struct {
+	gets, fastReturnHit, fastReturnMiss, handoffs, discarded uint64
+}
+
+func (p *Pool[T]) readCounterCut() counterCut {
+	return counterCut{
+		gets:           p.stats.totalGets.Load(),
+		fastReturnHit:  p.stats.FastReturnHit.Load(),
+		fastReturnMiss: p.stats.FastReturnMiss.Load(),
+		handoffs:       p.stats.waitQueueHandoffs.Load(),
+		discarded:      p.stats.discarded.Load(),
+	}
+}
+
+// consistentSnapshot takes a snapshot in SnapshotConsistent mode. It only takes the pool lock, never the
+// refill semaphore: Gets that find it taken wait for a refill instead of running their own.
+func (p *Pool[T]) consistentSnapshot() *PoolStatsSnapshot {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	var snapshot *PoolStatsSnapshot
+	for range maxConsistentSnapshotAttempts {
+		before := p.readCounterCut()
+		snapshot = p.readSnapshot()
+		if p.readCounterCut() == before {
+			break
+		}
+		runtime.Gosched()
+	}
+
+	return snapshot
+}
+
+// snapshot reads the statistics of the pool, see SnapshotFast.
+func (p *Pool[T]) snapshot() *PoolStatsSnapshot {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	return p.readSnapshot()
+}
+
+// readSnapshot is snapshot for callers holding the pool lock, the capacity, the object counts
+// and the ring buffer are replaced under it.
+func (p *Pool[T]) readSnapshot() *PoolStatsSnapshot {
 	fastReturnHit := p.stats.FastReturnHit.Load()
 	fastReturnMiss := p.stats.FastReturnMiss.Load()
 	totalReturns := fastReturnHit + fastReturnMiss
@@ -107,17 +253,44 @@ func (p *Pool[T]) GetPoolStatsSnapshot() *PoolStatsSnapshot {
 	ch := *chPtr
 	l1Len := len(ch)
 
-	totalPuts := p.stats.FastReturnHit.Load() + p.stats.FastReturnMiss.Load()
+	// returns are read before gets, every object returned was handed out first,
+	// so a Put racing with the snapshot can't make the returns overtake the gets.
+	totalPuts := p.stats.totalReturns()
 	totalGets := p.stats.totalGets.Load()
-	objectsInUse := totalGets - totalPuts
+
+	var objectsInUse uint64
+	if totalGets > totalPuts {
+		objectsInUse = totalGets - totalPuts
+	}
+
+	// the capacity can drop below the objects in use while the pool shrinks
+	currentCapacity := p.stats.currentCapacity
+	utilization := min(1, float64(objectsInUse)/float64(currentCapacity))
 
 	objectsCreated := p.stats.objectsCreated
 	objectsDestroyed := p.stats.objectsDestroyed
 
+	var activeSlabs, releasedSlabs int
+	if p.slabs != nil {
+		activeSlabs, releasedSlabs = p.slabs.counts()
+	}
+
+	var (
+		breakerState                     string
+		allocationFailures, circuitTrips int
+	)
+	if p.breaker != nil {
+		var state circuitState
+		state, allocationFailures, circuitTrips = p.breaker.snapshot()
+		breakerState = state.String()
+	}
+
 	return &PoolStatsSnapshot{
+		Timestamp: time.Now(),
+
 		// Basic Pool Stats
 		InitialCapacity:   p.stats.initialCapacity,
-		CurrentCapacity:   p.stats.currentCapacity,
+		CurrentCapacity:   currentCapacity,
 		ObjectsInUse:      objectsInUse,
 		TotalGets:         totalGets,
 		TotalGrowthEvents: p.stats.totalGrowthEvents,
@@ -139,16 +312,173 @@ func (p *Pool[T]) GetPoolStatsSnapshot() *PoolStatsSnapshot {
 		CurrentL1Capacity:       p.stats.currentL1Capacity,
 
 		// Derived Stats (computed from other fields)
-		AvailableObjects: p.stats.currentCapacity - int(objectsInUse),
+		AvailableObjects: max(0, currentCapacity-int(objectsInUse)),
 		RingBufferLength: p.pool.Length(false),
 		L1Length:         l1Len,
 		L2SpillRate:      l2SpillRate,
-		Utilization:      float64(objectsInUse) / float64(p.stats.currentCapacity),
+		Utilization:      utilization,
+
+		// Background Replenisher Stats
+		ReplenishRuns: p.stats.replenishRuns.Load(),
+
+		// Wait Queue Stats
+		Waiters:           p.waiters.len(),
+		TotalWaits:        p.waiters.totalWaits.Load(),
+		TotalWaitTime:     time.Duration(p.waiters.totalWaitTime.Load()),
+		MaxWaitTime:       time.Duration(p.waiters.maxWaitTime.Load()),
+		Reserved:          int(p.waiters.reserved.Load()),
+		WaitQueueHandoffs: p.stats.waitQueueHandoffs.Load(),
+
+		// Admission Control Stats
+		BlockedReaders: p.pool.GetBlockedReaders(),
+		WaitingCallers: int(p.waiting.Load()),
+		Overloaded:     p.stats.overloaded.Load(),
+
+		// Circuit Breaker Stats
+		CircuitState:       breakerState,
+		AllocationFailures: allocationFailures,
+		CircuitTrips:       circuitTrips,
+
+		// Callback Stats
+		CallbackPanics:   p.stats.callbackPanics.Load(),
+		DiscardedObjects: p.stats.discarded.Load(),
+
+		// Slab Stats
+		ActiveSlabs:   activeSlabs,
+		ReleasedSlabs: releasedSlabs,
+
+		// Get Path Stats
+		L1Hits:              p.stats.l1Hits.Load(),
+		L1Misses:            p.stats.l1Misses.Load(),
+		RefillAttempts:      p.stats.refillAttempts.Load(),
+		RefillSuccesses:     p.stats.refillSuccesses.Load(),
+		RefillFailures:      p.stats.refillFailures.Load(),
+		RefillGrowthBlocked: p.stats.refillGrowthBlocked.Load(),
+		OnDemandCreations:   p.stats.onDemandCreations.Load(),
+		SlowPathGets:        p.stats.slowPathGets.Load(),
+		SlowPathRetries:     p.stats.slowPathRetries.Load(),
+	}
+}
+
+// ExtendedPoolStatsSnapshot is a PoolStatsSnapshot with the statistics that are only collected when enabled.
+type ExtendedPoolStatsSnapshot struct {
+	*PoolStatsSnapshot
+
+	// GetLatency holds a latency histogram per Get path, keyed by GetPath name.
+	// Only populated when the Get latency histograms are enabled.
+	GetLatency map[string]LatencySnapshot `json:"get_latency,omitempty"`
+}
+
+// GetExtendedStatsSnapshot returns a snapshot of the current pool statistics, including the optional ones.
+func (p *Pool[T]) GetExtendedStatsSnapshot() *ExtendedPoolStatsSnapshot {
+	snapshot := &ExtendedPoolStatsSnapshot{PoolStatsSnapshot: p.GetPoolStatsSnapshot()}
+
+	if p.getLatency != nil {
+		snapshot.GetLatency = p.getLatency.snapshot()
+	}
+
+	return snapshot
+}
+
+// StatsDelta holds how much the counters of a pool moved between two snapshots.
+type StatsDelta struct {
+	// Interval is the time between the two snapshots
+	Interval time.Duration `json:"interval"`
+
+	Gets              uint64 `json:"gets"`
+	Returns           uint64 `json:"returns"` // objects given back, including the handed off and discarded ones
+	FastReturnHit     uint64 `json:"fast_return_hit"`
+	FastReturnMiss    uint64 `json:"fast_return_miss"`
+	WaitQueueHandoffs uint64 `json:"wait_queue_handoffs"`
+	GrowthEvents      uint64 `json:"growth_events"`
+	ShrinkEvents      uint64 `json:"shrink_events"`
+	ObjectsCreated    uint64 `json:"objects_created"`
+	ObjectsDestroyed  uint64 `json:"objects_destroyed"`
+
+	ReplenishRuns    uint64        `json:"replenish_runs"`
+	Waits            uint64        `json:"waits"`
+	WaitTime         time.Duration `json:"wait_time"`
+	Overloaded       uint64        `json:"overloaded"`
+	CircuitTrips     uint64        `json:"circuit_trips"`
+	CallbackPanics   uint64        `json:"callback_panics"`
+	DiscardedObjects uint64        `json:"discarded_objects"`
+
+	L1Hits              uint64 `json:"l1_hits"`
+	L1Misses            uint64 `json:"l1_misses"`
+	RefillAttempts      uint64 `json:"refill_attempts"`
+	RefillSuccesses     uint64 `json:"refill_successes"`
+	RefillFailures      uint64 `json:"refill_failures"`
+	RefillGrowthBlocked uint64 `json:"refill_growth_blocked"`
+	OnDemandCreations   uint64 `json:"on_demand_creations"`
+	SlowPathGets        uint64 `json:"slow_path_gets"`
+	SlowPathRetries     uint64 `json:"slow_path_retries"`
+}
+
+// PerSecond returns n as a rate over the interval of the delta, 0 if the interval is empty.
+func (d StatsDelta) PerSecond(n uint64) float64 {
+	if d.Interval <= 0 {
+		return 0
+	}
+	return float64(n) / d.Interval.Seconds()
+}
+
+// Delta returns how much the counters moved since prev, a snapshot of the same pool taken earlier.
+// A counter lower than in prev, which happens when prev belongs to another pool or the snapshots are passed
+// in the wrong order, yields 0 instead of wrapping around. A nil prev returns the counters since the pool was created.
+func (s *PoolStatsSnapshot) Delta(prev *PoolStatsSnapshot) StatsDelta {
+	if prev == nil {
+		prev = &PoolStatsSnapshot{Timestamp: s.Timestamp}
+	}
+
+	return StatsDelta{
+		Interval: s.Timestamp.Sub(prev.Timestamp),
+
+		Gets:              sub(s.TotalGets, prev.TotalGets),
+		Returns:           sub(s.TotalReturns(), prev.TotalReturns()),
+		FastReturnHit:     sub(s.FastReturnHit, prev.FastReturnHit),
+		FastReturnMiss:    sub(s.FastReturnMiss, prev.FastReturnMiss),
+		WaitQueueHandoffs: sub(s.WaitQueueHandoffs, prev.WaitQueueHandoffs),
+		GrowthEvents:      sub(s.TotalGrowthEvents, prev.TotalGrowthEvents),
+		ShrinkEvents:      sub(s.TotalShrinkEvents, prev.TotalShrinkEvents),
+		ObjectsCreated:    sub(s.ObjectsCreated, prev.ObjectsCreated),
+		ObjectsDestroyed:  sub(s.ObjectsDestroyed, prev.ObjectsDestroyed),
+
+		ReplenishRuns:    sub(s.ReplenishRuns, prev.ReplenishRuns),
+		Waits:            sub(s.TotalWaits, prev.TotalWaits),
+		WaitTime:         time.Duration(sub(s.TotalWaitTime, prev.TotalWaitTime)),
+		Overloaded:       sub(s.Overloaded, prev.Overloaded),
+		CircuitTrips:     sub(s.CircuitTrips, prev.CircuitTrips),
+		CallbackPanics:   sub(s.CallbackPanics, prev.CallbackPanics),
+		DiscardedObjects: sub(s.DiscardedObjects, prev.DiscardedObjects),
+
+		L1Hits:              sub(s.L1Hits, prev.L1Hits),
+		L1Misses:            sub(s.L1Misses, prev.L1Misses),
+		RefillAttempts:      sub(s.RefillAttempts, prev.RefillAttempts),
+		RefillSuccesses:     sub(s.RefillSuccesses, prev.RefillSuccesses),
+		RefillFailures:      sub(s.RefillFailures, prev.RefillFailures),
+		RefillGrowthBlocked: sub(s.RefillGrowthBlocked, prev.RefillGrowthBlocked),
+		OnDemandCreations:   sub(s.OnDemandCreations, prev.OnDemandCreations),
+		SlowPathGets:        sub(s.SlowPathGets, prev.SlowPathGets),
+		SlowPathRetries:     sub(s.SlowPathRetries, prev.SlowPathRetries),
+	}
+}
+
+// TotalReturns returns the number of objects given back to the pool: the fast returns, the objects
+// handed directly to queued callers and the ones discarded because the cleaner panicked.
+func (s *PoolStatsSnapshot) TotalReturns() uint64 {
+	return s.FastReturnHit + s.FastReturnMiss + s.WaitQueueHandoffs + s.DiscardedObjects
+}
+
+// sub returns cur - prev for a counter, 0 if it went backwards.
+func sub[N int | uint64 | time.Duration](cur, prev N) uint64 {
+	if cur <= prev {
+		return 0
 	}
+	return uint64(cur - prev)
 }
 
 func (s *PoolStatsSnapshot) Validate(reqNum int) error {
-	totalReturns := s.FastReturnHit + s.FastReturnMiss
+	totalReturns := s.TotalReturns()
 	if totalReturns != s.TotalGets {
 		return fmt.Errorf("total returns (%d) does not match total gets (%d)", totalReturns, s.TotalGets)
 	}
@@ -0,0 +1,146 @@
+package pool
+
+import (
+	"math"
+	"slices"
+	"sync"
+	"time"
+)
+
+// statsHistory is a bounded ring of snapshots taken at a fixed interval by the stats sampler.
+type statsHistory struct {
+	mu      sync.RWMutex
+	samples []*PoolStatsSnapshot
+	next    int
+	full    bool
+}
+
+func newStatsHistory(size int) *statsHistory {
+	return &statsHistory{samples: make([]*PoolStatsSnapshot, size)}
+}
+
+// add stores a snapshot, overwriting the oldest one once the history is full.
+func (h *statsHistory) add(snapshot *PoolStatsSnapshot) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.samples[h.next] = snapshot
+	h.next = (h.next + 1) % len(h.samples)
+	if h.next == 0 {
+		h.full = true
+	}
+}
+
+// list returns the stored snapshots, oldest first.
+func (h *statsHistory) list() []*PoolStatsSnapshot {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	if !h.full {
+		return slices.Clone(h.samples[:h.next])
+	}
+
+	return append(slices.Clone(h.samples[h.next:]), h.samples[:h.next]...)
+}
+
+// sampleStats is a background goroutine that records a snapshot of the pool statistics every interval.
+func (p *Pool[T]) sampleStats() {
+	p.labelGoroutine(roleStatsHistory)
+
+	ticker := time.NewTicker(p.config.statsHistoryInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-p.ctx.Done():
+			return
+		case <-ticker.C:
+			p.history.add(p.GetPoolStatsSnapshot())
+		}
+	}
+}
+
+// StatsHistory returns the snapshots recorded by the stats sampler, oldest first.
+// Returns nil if the stats history is disabled.
+func (p *Pool[T]) StatsHistory() []*PoolStatsSnapshot {
+	if p.history == nil {
+		return nil
+	}
+
+	return p.history.list()
+}
+
+// StatsWindow summarizes the stats history over a window of time.
+type StatsWindow struct {
+	Start   time.Time `json:"start"`
+	End     time.Time `json:"end"`
+	Samples int       `json:"samples"`
+
+	// Rates over the window, zero when it holds a single sample.
+	GetsPerSecond         float64 `json:"gets_per_second"`
+	AllocationsPerSecond  float64 `json:"allocations_per_second"`
+	GrowthEventsPerMinute float64 `json:"growth_events_per_minute"`
+
+	// Objects in use across the samples of the window.
+	InUseMin uint64 `json:"in_use_min"`
+	InUseMax uint64 `json:"in_use_max"`
+	InUseP50 uint64 `json:"in_use_p50"`
+	InUseP90 uint64 `json:"in_use_p90"`
+	InUseP99 uint64 `json:"in_use_p99"`
+}
+
+// StatsWindow summarizes the snapshots recorded during the last window, or the whole history
+// if window is zero or negative. Returns false if the stats history is disabled or empty.
+func (p *Pool[T]) StatsWindow(window time.Duration) (StatsWindow, bool) {
+	samples := p.StatsHistory()
+	if len(samples) == 0 {
+		return StatsWindow{}, false
+	}
+
+	if window > 0 {
+		since := samples[len(samples)-1].Timestamp.Add(-window)
+		first, _ := slices.BinarySearchFunc(samples, since, func(s *PoolStatsSnapshot, t time.Time) int {
+			return s.Timestamp.Compare(t)
+		})
+		samples = samples[first:]
+	}
+
+	return summarizeStats(samples), true
+}
+
+// summarizeStats computes the rates and in-use distribution of a non-empty list of snapshots, oldest first.
+func summarizeStats(samples []*PoolStatsSnapshot) StatsWindow {
+	first, last := samples[0], samples[len(samples)-1]
+
+	summary := StatsWindow{
+		Start:   first.Timestamp,
+		End:     last.Timestamp,
+		Samples: len(samples),
+	}
+
+	if delta := last.Delta(first); delta.Interval > 0 {
+		summary.GetsPerSecond = delta.PerSecond(delta.Gets)
+		summary.AllocationsPerSecond = delta.PerSecond(delta.ObjectsCreated)
+		summary.GrowthEventsPerMinute = float64(delta.GrowthEvents) / delta.Interval.Minutes()
+	}
+
+	inUse := make([]uint64, len(samples))
+	for i, sample := range samples {
+		inUse[i] = sample.ObjectsInUse
+	}
+	slices.Sort(inUse)
+
+	summary.InUseMin = inUse[0]
+	summary.InUseMax = inUse[len(inUse)-1]
+	summary.InUseP50 = nearestRank(inUse, 0.50)
+	summary.InUseP90 = nearestRank(inUse, 0.90)
+	summary.InUseP99 = nearestRank(inUse, 0.99)
+
+	return summary
+}
+
+// nearestRank returns the q percentile of sorted values using the nearest-rank method.
+func nearestRank(sorted []uint64, q float64) uint64 {
+	rank := int(math.Ceil(q*float64(len(sorted)))) - 1
+	return sorted[max(0, min(rank, len(sorted)-1))]
+}
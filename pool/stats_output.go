@@ -0,0 +1,250 @@
+package pool
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"text/tabwriter"
+)
+
+// Format selects how WriteStats renders the pool statistics.
+type Format int
+
+const (
+	FormatText  Format = iota // one "Label: value" line per statistic, the format of PrintPoolStats
+	FormatJSON                // a single line JSON object, keyed by the snake_case names of the snapshot fields
+	FormatTable               // statistics and values in aligned columns
+)
+
+func (f Format) String() string {
+	switch f {
+	case FormatText:
+		return "text"
+	case FormatJSON:
+		return "json"
+	case FormatTable:
+		return "table"
+	default:
+		return fmt.Sprintf("Format(%d)", int(f))
+	}
+}
+
+// statsSection selects the optional groups of statistics rendered as text or table.
+type statsSection uint
+
+const (
+	sectionReplenisher statsSection = 1 << iota
+	sectionWaitQueue
+	sectionAdmission
+	sectionCircuitBreaker
+	sectionSlabs
+
+	allStatsSections = sectionReplenisher | sectionWaitQueue | sectionAdmission | sectionCircuitBreaker | sectionSlabs
+)
+
+// statsRow is a single rendered statistic.
+type statsRow struct {
+	label string
+	value string
+}
+
+// WriteStats writes the current statistics of the pool to w in the given format.
+// Text and table output only include the groups of statistics that apply to the pool configuration,
+// and the wait queue statistics once callers went through the queue. JSON output always includes every field.
+func (p *Pool[T]) WriteStats(w io.Writer, format Format) error {
+	stats := p.GetExtendedStatsSnapshot()
+
+	switch format {
+	case FormatText:
+		return writeStatsText(w, stats.rows(p.statsSections(stats.PoolStatsSnapshot)))
+	case FormatTable:
+		return writeStatsTable(w, stats.rows(p.statsSections(stats.PoolStatsSnapshot)))
+	case FormatJSON:
+		return json.NewEncoder(w).Encode(stats)
+	default:
+		return fmt.Errorf("unknown stats format: %s", format)
+	}
+}
+
+// statsSections returns the optional groups of statistics that apply to the pool configuration,
+// or to what stats shows the pool went through.
+func (p *Pool[T]) statsSections(stats *PoolStatsSnapshot) statsSection {
+	var sections statsSection
+
+	if p.replenishSignal != nil {
+		sections |= sectionReplenisher
+	}
+
+	if stats.Waiters > 0 || stats.TotalWaits > 0 {
+		sections |= sectionWaitQueue
+	}
+
+	if p.config.maxWaiters > 0 || p.config.queueTimeBudget > 0 {
+		sections |= sectionAdmission
+	}
+
+	if p.breaker != nil {
+		sections |= sectionCircuitBreaker
+	}
+
+	if p.slabs != nil {
+		sections |= sectionSlabs
+	}
+
+	return sections
+}
+
+// rows renders the statistics, including the given optional groups.
+func (s *PoolStatsSnapshot) rows(sections statsSection) []statsRow {
+	rows := []statsRow{
+		{"Objects in use", fmt.Sprintf("%d", s.ObjectsInUse)},
+		{"Objects created", fmt.Sprintf("%d", s.ObjectsCreated)},
+		{"Objects destroyed", fmt.Sprintf("%d", s.ObjectsDestroyed)},
+		{"Available objects", fmt.Sprintf("%d", s.AvailableObjects)},
+		{"Current capacity", fmt.Sprintf("%d", s.CurrentCapacity)},
+		{"Ring buffer length", fmt.Sprintf("%d", s.RingBufferLength)},
+		{"Total gets", fmt.Sprintf("%d", s.TotalGets)},
+		{"Total growth events", fmt.Sprintf("%d", s.TotalGrowthEvents)},
+		{"Total shrink events", fmt.Sprintf("%d", s.TotalShrinkEvents)},
+		{"Consecutive shrinks", fmt.Sprintf("%d", s.ConsecutiveShrinks)},
+		{"L1 cache capacity", fmt.Sprintf("%d", s.CurrentL1Capacity)},
+		{"L1 cache length", fmt.Sprintf("%d", s.L1Length)},
+		{"Fast return hit", fmt.Sprintf("%d", s.FastReturnHit)},
+		{"Fast return miss", fmt.Sprintf("%d", s.FastReturnMiss)},
+		{"L2 spill rate", fmt.Sprintf("%.2f%%", s.L2SpillRate*100)},
+		{"Utilization", fmt.Sprintf("%.2f%%", s.Utilization*100)},
+		{"Last shrink time", fmt.Sprintf("%v", s.LastShrinkTime)},
+	}
+
+	if sections&sectionReplenisher != 0 {
+		rows = append(rows,
+			statsRow{"Replenish runs", fmt.Sprintf("%d", s.ReplenishRuns)},
+		)
+	}
+
+	if sections&sectionWaitQueue != 0 {
+		rows = append(rows,
+			statsRow{"Waiters", fmt.Sprintf("%d", s.Waiters)},
+			statsRow{"Total waits", fmt.Sprintf("%d", s.TotalWaits)},
+			statsRow{"Total wait time", fmt.Sprintf("%v", s.TotalWaitTime)},
+			statsRow{"Max wait time", fmt.Sprintf("%v", s.MaxWaitTime)},
+			statsRow{"Wait queue handoffs", fmt.Sprintf("%d", s.WaitQueueHandoffs)},
+		)
+	}
+
+	if sections&sectionAdmission != 0 {
+		rows = append(rows,
+			statsRow{"Blocked readers", fmt.Sprintf("%d", s.BlockedReaders)},
+			statsRow{"Waiting callers", fmt.Sprintf("%d", s.WaitingCallers)},
+			statsRow{"Overloaded", fmt.Sprintf("%d", s.Overloaded)},
+		)
+	}
+
+	if sections&sectionCircuitBreaker != 0 {
+		rows = append(rows,
+			statsRow{"Circuit state", s.CircuitState},
+			statsRow{"Allocation failures", fmt.Sprintf("%d", s.AllocationFailures)},
+			statsRow{"Circuit trips", fmt.Sprintf("%d", s.CircuitTrips)},
+		)
+	}
+
+	if s.CallbackPanics > 0 {
+		rows = append(rows,
+			statsRow{"Callback panics", fmt.Sprintf("%d", s.CallbackPanics)},
+			statsRow{"Discarded objects", fmt.Sprintf("%d", s.DiscardedObjects)},
+		)
+	}
+
+	if sections&sectionSlabs != 0 {
+		rows = append(rows,
+			statsRow{"Active slabs", fmt.Sprintf("%d", s.ActiveSlabs)},
+			statsRow{"Released slabs", fmt.Sprintf("%d", s.ReleasedSlabs)},
+		)
+	}
+
+	return append(rows,
+		statsRow{"L1 hits", fmt.Sprintf("%d (misses %d)", s.L1Hits, s.L1Misses)},
+		statsRow{"Refill attempts", fmt.Sprintf("%d (succeeded %d, failed %d, growth blocked %d)",
+			s.RefillAttempts, s.RefillSuccesses, s.RefillFailures, s.RefillGrowthBlocked)},
+		statsRow{"On-demand creations", fmt.Sprintf("%d", s.OnDemandCreations)},
+		statsRow{"Slow path gets", fmt.Sprintf("%d (retries %d)", s.SlowPathGets, s.SlowPathRetries)},
+	)
+}
+
+// rows renders the statistics and the latency of every Get path that was taken at least once.
+func (s *ExtendedPoolStatsSnapshot) rows(sections statsSection) []statsRow {
+	rows := s.PoolStatsSnapshot.rows(sections)
+
+	for path := range numGetPaths {
+		latency, ok := s.GetLatency[path.String()]
+		if !ok || latency.Count == 0 {
+			continue
+		}
+
+		rows = append(rows, statsRow{
+			label: fmt.Sprintf("Get latency (%s)", path),
+			value: fmt.Sprintf("count %d, mean %v, p99 %v, max %v",
+				latency.Count, latency.Mean(), latency.Quantile(0.99), latency.Max),
+		})
+	}
+
+	return rows
+}
+
+func writeStatsText(w io.Writer, rows []statsRow) error {
+	var buf bytes.Buffer
+
+	buf.WriteString("=== Pool Statistics ===\n")
+	for _, row := range rows {
+		fmt.Fprintf(&buf, "%s: %s\n", row.label, row.value)
+	}
+	buf.WriteString("===================\n")
+
+	_, err := w.Write(buf.Bytes())
+	return err
+}
+
+func writeStatsTable(w io.Writer, rows []statsRow) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+
+	fmt.Fprintln(tw, "STATISTIC\tVALUE")
+	for _, row := range rows {
+		fmt.Fprintf(tw, "%s\t%s\n", row.label, row.value)
+	}
+
+	return tw.Flush()
+}
+
+// snapshotFields has the fields of PoolStatsSnapshot without its methods, so it's encoded field by field.
+type snapshotFields PoolStatsSnapshot
+
+// MarshalJSON encodes the snapshot with the snake_case field names of its json tags.
+func (s *PoolStatsSnapshot) MarshalJSON() ([]byte, error) {
+	return json.Marshal((*snapshotFields)(s))
+}
+
+// MarshalText renders the snapshot in the text format of WriteStats, with every group of statistics.
+func (s *PoolStatsSnapshot) MarshalText() ([]byte, error) {
+	var buf bytes.Buffer
+	err := writeStatsText(&buf, s.rows(allStatsSections))
+	return buf.Bytes(), err
+}
+
+// MarshalJSON encodes the snapshot with the fields of PoolStatsSnapshot and the Get latency histograms.
+func (s *ExtendedPoolStatsSnapshot) MarshalJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		*snapshotFields
+		GetLatency map[string]LatencySnapshot `json:"get_latency,omitempty"`
+	}{
+		snapshotFields: (*snapshotFields)(s.PoolStatsSnapshot),
+		GetLatency:     s.GetLatency,
+	})
+}
+
+// MarshalText renders the snapshot in the text format of WriteStats, with every group of statistics.
+func (s *ExtendedPoolStatsSnapshot) MarshalText() ([]byte, error) {
+	var buf bytes.Buffer
+	err := writeStatsText(&buf, s.rows(allStatsSections))
+	return buf.Bytes(), err
+}
@@ -2,6 +2,8 @@ package pool
 
 import (
 	"context"
+	"log/slog"
+	"runtime/pprof"
 	"sync"
 	"sync/atomic"
 	"time"
@@ -35,12 +37,22 @@ type Pool[T any] struct {
 
 	refillSemaphore chan struct{}
 
+	// replenishSignal wakes up the background replenisher, nil when it's disabled.
+	replenishSignal chan struct{}
+
 	// shrinkCond is used for blocking shrink when it reaches maxConsecutiveShrinks
 	shrinkCond *sync.Cond
 
 	// refillCond is used for blocking multiple goroutines while one goroutine is refilling the pool
 	refillCond *sync.Cond
 
+	// waiters queues blocked Get callers in fair mode, returned objects are handed directly to them
+	waiters *waitQueue[T]
+
+	// waiting counts the callers currently waiting for an object, on refillCond, in the ring buffer
+	// or in the wait queue. It's what maxWaiters is enforced against.
+	waiting atomic.Int64
+
 	// stats tracks essential pool statistics for the functionallity of the pool
 	stats *poolStats
 
@@ -63,6 +75,30 @@ type Pool[T any] struct {
 	// template is a template object that is used to create new objects
 	template T
 
+	// listener receives the lifecycle events of the pool, a NopListener when none is configured.
+	listener Listener
+
+	// metrics are the handles of the configured MetricsSink, no-ops when none is configured.
+	metrics *poolMetrics
+
+	// getLatency records the latency of Get calls by path, nil when it's disabled.
+	getLatency *getLatency
+
+	// logger receives the pool's internal diagnostics, tagged with the pool name.
+	logger *slog.Logger
+
+	// history keeps the snapshots recorded by the stats sampler, nil when it's disabled.
+	history *statsHistory
+
+	// outstanding records the stack of every Get whose object wasn't returned yet, nil when it's disabled.
+	outstanding *pprof.Profile
+
+	// breaker stops calling the allocator after repeated failures, nil when it's disabled.
+	breaker *circuitBreaker
+
+	// slabs keeps track of slab membership when slab allocation is enabled, nil otherwise.
+	slabs *slabAllocator[T]
+
 	// ctx and cancel manage the pool's lifecycle
 	ctx    context.Context
 	cancel context.CancelFunc
@@ -100,6 +136,58 @@ type PoolConfig[T any] struct {
 
 	// allocationStrategy configures how the pool allocates objects.
 	allocationStrategy *AllocationStrategy
+
+	// slabInitializer initializes slab members in place, nil leaves them as the zero value.
+	slabInitializer func(T)
+
+	// fairWaiting makes blocked Get callers wait in the wait queue instead of the ring buffer, and join it
+	// when others are already waiting instead of taking an idle object ahead of them. Only applies in blocking mode.
+	fairWaiting bool
+
+	// maxWaiters caps the number of callers waiting for an object, 0 means unlimited.
+	// Callers beyond the cap fail fast with ErrOverloaded.
+	maxWaiters int
+
+	// queueTimeBudget bounds how long a caller waits for an object before failing
+	// with ErrOverloaded, 0 means no budget.
+	queueTimeBudget time.Duration
+
+	// circuitBreakerThreshold is the number of consecutive allocation failures or panics
+	// that open the allocation circuit, 0 disables the circuit breaker.
+	circuitBreakerThreshold int
+
+	// circuitBreakerOpenDuration is how long the circuit stays open before a probe allocation is attempted.
+	circuitBreakerOpenDuration time.Duration
+
+	// panicHandler is called with every panic recovered from the allocator, cleaner, cloneTemplate or slab initializer.
+	panicHandler func(CallbackPanic)
+
+	// listener receives the lifecycle events of the pool.
+	listener Listener
+
+	// metricsSink creates the metric handles updated by the pool.
+	metricsSink MetricsSink
+
+	// getLatencyHistograms records the latency of every Get call, by the path it took.
+	getLatencyHistograms bool
+
+	// profilerLabels labels the goroutines running the pool's background work, and the GetPriority and GetAtomic calls, for CPU profiles.
+	profilerLabels bool
+
+	// outstandingProfile records the outstanding objects in a pprof profile.
+	outstandingProfile bool
+
+	// name identifies the pool in logs.
+	name string
+
+	// logger receives the pool's internal diagnostics, nothing is logged when it's nil.
+	logger *slog.Logger
+
+	// statsHistoryInterval is how often the stats sampler records a snapshot, 0 disables the history.
+	statsHistoryInterval time.Duration
+
+	// statsHistorySize is the number of snapshots kept by the stats sampler.
+	statsHistorySize int
 }
 
 // Getter methods for PoolConfig
@@ -127,6 +215,74 @@ func (c *PoolConfig[T]) GetRingBufferConfig() *config.RingBufferConfig[T] {
 	return c.ringBufferConfig
 }
 
+func (c *PoolConfig[T]) GetAllocationStrategy() *AllocationStrategy {
+	return c.allocationStrategy
+}
+
+func (c *PoolConfig[T]) GetSlabInitializer() func(T) {
+	return c.slabInitializer
+}
+
+func (c *PoolConfig[T]) IsFairWaiting() bool {
+	return c.fairWaiting
+}
+
+func (c *PoolConfig[T]) GetMaxWaiters() int {
+	return c.maxWaiters
+}
+
+func (c *PoolConfig[T]) GetQueueTimeBudget() time.Duration {
+	return c.queueTimeBudget
+}
+
+func (c *PoolConfig[T]) GetCircuitBreakerThreshold() int {
+	return c.circuitBreakerThreshold
+}
+
+func (c *PoolConfig[T]) GetCircuitBreakerOpenDuration() time.Duration {
+	return c.circuitBreakerOpenDuration
+}
+
+func (c *PoolConfig[T]) GetPanicHandler() func(CallbackPanic) {
+	return c.panicHandler
+}
+
+func (c *PoolConfig[T]) GetListener() Listener {
+	return c.listener
+}
+
+func (c *PoolConfig[T]) GetMetricsSink() MetricsSink {
+	return c.metricsSink
+}
+
+func (c *PoolConfig[T]) IsGetLatencyHistogramsEnabled() bool {
+	return c.getLatencyHistograms
+}
+
+func (c *PoolConfig[T]) IsProfilerLabelsEnabled() bool {
+	return c.profilerLabels
+}
+
+func (c *PoolConfig[T]) IsOutstandingProfileEnabled() bool {
+	return c.outstandingProfile
+}
+
+func (c *PoolConfig[T]) GetName() string {
+	return c.name
+}
+
+func (c *PoolConfig[T]) GetLogger() *slog.Logger {
+	return c.logger
+}
+
+func (c *PoolConfig[T]) GetStatsHistoryInterval() time.Duration {
+	return c.statsHistoryInterval
+}
+
+func (c *PoolConfig[T]) GetStatsHistorySize() int {
+	return c.statsHistorySize
+}
+
 // growthParameters controls how the pool expands to meet demand.
 // It supports both exponential and fixed growth strategies to balance
 // between rapid growth for high demand and controlled growth for stability.
@@ -279,6 +435,14 @@ type fastPathParameters struct {
 	// enableChannelGrowth allows dynamic resizing of the fast path channel.
 	// When enabled, the L1 cache can grow and shrink based on usage patterns.
 	enableChannelGrowth bool
+
+	// backgroundReplenish enables a background goroutine that keeps the L1 cache stocked,
+	// so callers on the L1 hit path never pay the refill latency.
+	backgroundReplenish bool
+
+	// replenishInterval is how often the background replenisher checks the L1 occupancy
+	// when it isn't woken up by a Get.
+	replenishInterval time.Duration
 }
 
 // Getter methods for fastPathParameters
@@ -318,6 +482,14 @@ func (f *fastPathParameters) GetPreReadBlockHookAttempts() int {
 	return f.preReadBlockHookAttempts
 }
 
+func (f *fastPathParameters) IsBackgroundReplenishEnabled() bool {
+	return f.backgroundReplenish
+}
+
+func (f *fastPathParameters) GetReplenishInterval() time.Duration {
+	return f.replenishInterval
+}
+
 // shrinkDefaults provides default values for shrink parameters.
 // These defaults are used when specific parameters are not configured.
 type shrinkDefaults struct {
@@ -343,9 +515,15 @@ type shrinkDefaults struct {
 type AllocationStrategy struct {
 	// The percentage of objects to preallocate at initialization
 	// The percentage of objects to fill the pool up to when growing
-	AllocPercent int
+	AllocPercent int `json:"alloc_percent"`
 
 	// The amount of objects to create per request
 	// If it exceeds the ring buffer capacity it will be adjusted to the ring buffer capacity.
-	AllocAmount int
+	// When slab allocation is enabled, it is also the number of objects per slab.
+	AllocAmount int `json:"alloc_amount"`
+
+	// SlabAllocation makes the pool carve objects out of contiguous slabs ([]E, where T is *E)
+	// instead of allocating them one by one. Slab members start as the zero value of E and are
+	// initialized in place by the slab initializer, the allocator and the cloner aren't called for them.
+	SlabAllocation bool `json:"slab_allocation"`
 }
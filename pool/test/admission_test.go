@@ -0,0 +1,133 @@
+package test
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/AlexsanderHamir/PoolX/v2/pool"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// the callers wait in the wait queue in fair mode, and block in the ring buffer otherwise
+func TestMaxWaitersRejectsExcessCallers(t *testing.T) {
+	t.Run("wait queue", func(t *testing.T) { testMaxWaitersRejectsExcessCallers(t, true) })
+	t.Run("ring buffer", func(t *testing.T) { testMaxWaitersRejectsExcessCallers(t, false) })
+}
+
+func testMaxWaitersRejectsExcessCallers(t *testing.T, fair bool) {
+	const maxWaiters = 2
+
+	p, objects := createExhaustedPool(t, 2, func(b pool.PoolConfigBuilder[*TestObject]) pool.PoolConfigBuilder[*TestObject] {
+		return b.SetFairWaiting(fair).SetMaxWaiters(maxWaiters)
+	})
+	defer func() {
+		require.NoError(t, p.Close())
+	}()
+
+	var wg sync.WaitGroup
+	for range maxWaiters {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			obj, err := p.Get()
+			assert.NoError(t, err)
+			assert.NoError(t, p.Put(obj))
+		}()
+	}
+	require.Eventually(t, func() bool {
+		return p.GetPoolStatsSnapshot().WaitingCallers == maxWaiters
+	}, time.Second, time.Millisecond)
+
+	start := time.Now()
+	obj, err := p.Get()
+	assert.ErrorIs(t, err, pool.ErrOverloaded)
+	assert.Nil(t, obj)
+	assert.Less(t, time.Since(start), 100*time.Millisecond)
+
+	for _, obj := range objects {
+		require.NoError(t, p.Put(obj))
+	}
+	wg.Wait()
+
+	stats := p.GetPoolStatsSnapshot()
+	assert.Equal(t, uint64(1), stats.Overloaded)
+	assert.Equal(t, 0, stats.WaitingCallers)
+}
+
+func TestQueueTimeBudget(t *testing.T) {
+	t.Run("wait queue", func(t *testing.T) { testQueueTimeBudget(t, true) })
+	t.Run("ring buffer", func(t *testing.T) { testQueueTimeBudget(t, false) })
+}
+
+func testQueueTimeBudget(t *testing.T, fair bool) {
+	const budget = 30 * time.Millisecond
+
+	p, objects := createExhaustedPool(t, 2, func(b pool.PoolConfigBuilder[*TestObject]) pool.PoolConfigBuilder[*TestObject] {
+		return b.SetFairWaiting(fair).SetQueueTimeBudget(budget)
+	})
+	defer func() {
+		require.NoError(t, p.Close())
+	}()
+
+	start := time.Now()
+	obj, err := p.Get()
+	elapsed := time.Since(start)
+
+	assert.ErrorIs(t, err, pool.ErrOverloaded)
+	assert.Nil(t, obj)
+	assert.GreaterOrEqual(t, elapsed, budget)
+	assert.Less(t, elapsed, time.Second)
+
+	stats := p.GetPoolStatsSnapshot()
+	assert.Equal(t, uint64(1), stats.Overloaded)
+	assert.Equal(t, 0, stats.Waiters)
+	assert.Equal(t, 0, stats.WaitingCallers)
+
+	for _, obj := range objects {
+		require.NoError(t, p.Put(obj))
+	}
+
+	obj, err = p.Get()
+	require.NoError(t, err)
+	require.NoError(t, p.Put(obj))
+}
+
+func TestNonBlockingCallersAreNotAdmitted(t *testing.T) {
+	listener := &recordingListener{}
+	p, objects := createExhaustedPool(t, 2, func(b pool.PoolConfigBuilder[*TestObject]) pool.PoolConfigBuilder[*TestObject] {
+		return b.SetRingBufferBlocking(false).SetMaxWaiters(1).SetListener(listener)
+	})
+	defer func() {
+		require.NoError(t, p.Close())
+	}()
+
+	// nothing waits in a non-blocking pool, the callers fail without taking a waiting slot
+	for range 3 {
+		obj, err := p.Get()
+		assert.Error(t, err)
+		assert.NotErrorIs(t, err, pool.ErrOverloaded)
+		assert.Nil(t, obj)
+
+		objs, err := p.GetAtomic(context.Background(), 1)
+		assert.Error(t, err)
+		assert.NotErrorIs(t, err, pool.ErrOverloaded)
+		assert.Nil(t, objs)
+	}
+
+	stats := p.GetPoolStatsSnapshot()
+	assert.Zero(t, stats.Overloaded)
+	assert.Zero(t, stats.WaitingCallers)
+
+	listener.mu.Lock()
+	assert.Zero(t, listener.waitStarts)
+	assert.Zero(t, listener.waitEnds)
+	listener.mu.Unlock()
+
+	for _, obj := range objects {
+		require.NoError(t, p.Put(obj))
+	}
+}
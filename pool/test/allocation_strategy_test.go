@@ -177,3 +177,153 @@ func TestAllocationStrategyWithShrink(t *testing.T) {
 	assert.Equal(t, uint64(10), stats.FastReturnHit+stats.FastReturnMiss) // All objects should be returned
 	assert.Equal(t, 25, stats.ObjectsCreated)                             // No new objects should be created
 }
+
+func TestSlabAllocation(t *testing.T) {
+	config, err := pool.NewPoolConfigBuilder[*TestObject]().
+		SetInitialCapacity(20).
+		SetHardLimit(100).
+		SetAllocationStrategy(100, 5). // 100% preallocation, 5 objects per slab
+		SetSlabAllocation(true).
+		SetSlabInitializer(func(obj *TestObject) { obj.Value = 42 }).
+		SetMinShrinkCapacity(10).
+		Build()
+	require.NoError(t, err)
+	assert.True(t, config.GetAllocationStrategy().SlabAllocation)
+
+	p := createTestPool(t, config)
+	defer func() {
+		require.NoError(t, p.Close())
+	}()
+
+	stats := p.GetPoolStatsSnapshot()
+	assert.Equal(t, 20, stats.ObjectsCreated)
+	assert.Equal(t, 4, stats.ActiveSlabs) // 20 objects / 5 per slab
+
+	objects := make([]*TestObject, 30)
+	for i := range objects {
+		objects[i], err = p.Get()
+		require.NoError(t, err)
+		require.NotNil(t, objects[i])
+		assert.Equal(t, 42, objects[i].Value) // initialized in place
+	}
+
+	stats = p.GetPoolStatsSnapshot()
+	assert.True(t, p.IsGrowth())
+	assert.GreaterOrEqual(t, stats.ActiveSlabs, (stats.ObjectsCreated+4)/5) // slabs hold at most 5 objects
+	assert.LessOrEqual(t, stats.ActiveSlabs, stats.ObjectsCreated)
+
+	for _, obj := range objects {
+		require.NoError(t, p.Put(obj))
+	}
+}
+
+func TestSlabAllocationInitializesMembersInPlace(t *testing.T) {
+	var initialized int
+	config, err := pool.NewPoolConfigBuilder[*TestBuffer]().
+		SetInitialCapacity(10).
+		SetHardLimit(10).
+		SetAllocationStrategy(100, 5).
+		SetSlabAllocation(true).
+		SetSlabInitializer(func(obj *TestBuffer) {
+			initialized++
+			obj.Data = make([]byte, 8)
+			obj.Size = 8
+		}).
+		SetMinShrinkCapacity(10).
+		Build()
+	require.NoError(t, err)
+
+	var allocations int
+	allocator := func() *TestBuffer {
+		allocations++
+		return &TestBuffer{Data: make([]byte, 8), Size: 8}
+	}
+	cleaner := func(obj *TestBuffer) {}
+
+	poolObj, err := pool.NewPool(config, allocator, cleaner, nil)
+	require.NoError(t, err)
+	p := poolObj.(*pool.Pool[*TestBuffer])
+	defer func() {
+		require.NoError(t, p.Close())
+	}()
+
+	assert.Equal(t, 2, p.GetPoolStatsSnapshot().ActiveSlabs)
+	assert.Equal(t, 10, initialized, "one initializer call per slab member")
+	assert.LessOrEqual(t, allocations, 2, "the allocator isn't called for slab members")
+
+	a, err := p.Get()
+	require.NoError(t, err)
+	b, err := p.Get()
+	require.NoError(t, err)
+
+	a.Data[0] = 1
+	assert.Equal(t, 8, b.Size)
+	assert.Equal(t, byte(0), b.Data[0], "slab members don't share the objects they reference")
+
+	require.NoError(t, p.Put(a))
+	require.NoError(t, p.Put(b))
+}
+
+func TestSlabAllocationReducesAllocations(t *testing.T) {
+	const objects = 512
+
+	allocsPerPool := func(slab bool) float64 {
+		config, err := pool.NewPoolConfigBuilder[*TestObject]().
+			SetInitialCapacity(objects).
+			SetHardLimit(objects).
+			SetAllocationStrategy(100, objects).
+			SetSlabAllocation(slab).
+			SetMinShrinkCapacity(objects).
+			Build()
+		require.NoError(t, err)
+
+		allocator := func() *TestObject { return &TestObject{} }
+		cleaner := func(obj *TestObject) {}
+
+		return testing.AllocsPerRun(10, func() {
+			p, err := pool.NewPool(config, allocator, cleaner, nil)
+			require.NoError(t, err)
+			require.NoError(t, p.Close())
+		})
+	}
+
+	perObject := allocsPerPool(false)
+	slab := allocsPerPool(true)
+
+	t.Logf("allocations per pool of %d objects: %.0f per object, %.0f with slabs", objects, perObject, slab)
+	assert.Less(t, slab, perObject-objects/2, "a slab replaces the per-object allocations")
+}
+
+func TestSlabAllocationWithShrink(t *testing.T) {
+	config, err := pool.NewPoolConfigBuilder[*TestObject]().
+		SetInitialCapacity(50).
+		SetHardLimit(100).
+		SetAllocationStrategy(100, 5).
+		SetSlabAllocation(true).
+		SetMinShrinkCapacity(5).
+		SetShrinkCheckInterval(10*time.Millisecond).
+		SetShrinkCooldown(10*time.Millisecond).
+		SetMinUtilizationBeforeShrink(90).
+		SetStableUnderutilizationRounds(1).
+		SetShrinkPercent(50).
+		SetFastPathBasicConfigs(10, 1, 1, 100, 20).
+		SetFastPathShrinkConfigs(50, 1).
+		Build()
+	require.NoError(t, err)
+
+	p := createTestPool(t, config)
+	defer func() {
+		require.NoError(t, p.Close())
+	}()
+
+	stats := p.GetPoolStatsSnapshot()
+	assert.Equal(t, 10, stats.ActiveSlabs)
+
+	time.Sleep(200 * time.Millisecond)
+
+	stats = p.GetPoolStatsSnapshot()
+	assert.True(t, p.IsShrunk())
+	assert.Greater(t, stats.ObjectsDestroyed, 0)
+	assert.Greater(t, stats.ReleasedSlabs, 0)
+	assert.Equal(t, 10, stats.ActiveSlabs+stats.ReleasedSlabs)
+}
@@ -198,3 +198,58 @@ func TestDisabledChannelGrowth(t *testing.T) {
 		p.Put(obj)
 	}
 }
+
+func TestBackgroundReplenisher(t *testing.T) {
+	config, err := pool.NewPoolConfigBuilder[*TestObject]().
+		SetInitialCapacity(100).
+		SetMinShrinkCapacity(10).
+		SetFastPathBasicConfigs(20, 1, 1, 100, 50).
+		SetFastPathBackgroundReplenisher(true, 5*time.Millisecond).
+		SetAllocationStrategy(20, 10).
+		Build()
+	require.NoError(t, err)
+	assert.True(t, config.GetFastPath().IsBackgroundReplenishEnabled())
+
+	p := createTestPool(t, config)
+	defer func() {
+		require.NoError(t, p.Close())
+	}()
+
+	stats := p.GetPoolStatsSnapshot()
+	require.Equal(t, 20, stats.L1Length)
+
+	// drain L1 below the refill threshold (50% of 20) without missing it
+	objects := make([]*TestObject, 0, 25)
+	for range 15 {
+		obj, err := p.Get()
+		require.NoError(t, err)
+		require.NotNil(t, obj)
+		objects = append(objects, obj)
+	}
+
+	// only the replenisher refills L1 without a Get missing it
+	require.Eventually(t, func() bool {
+		return p.GetPoolStatsSnapshot().L1Length >= 10
+	}, time.Second, 5*time.Millisecond)
+
+	stats = p.GetPoolStatsSnapshot()
+	assert.Greater(t, stats.ReplenishRuns, uint64(0))
+	assert.Equal(t, uint64(0), stats.L1Misses)
+	l1Hits := stats.L1Hits
+
+	// more Gets than L1 held after the drain, they all hit the replenished L1
+	for range 10 {
+		obj, err := p.Get()
+		require.NoError(t, err)
+		require.NotNil(t, obj)
+		objects = append(objects, obj)
+	}
+
+	stats = p.GetPoolStatsSnapshot()
+	assert.Equal(t, l1Hits+10, stats.L1Hits)
+	assert.Equal(t, uint64(0), stats.L1Misses)
+
+	for _, obj := range objects {
+		require.NoError(t, p.Put(obj))
+	}
+}
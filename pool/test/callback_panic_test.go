@@ -0,0 +1,185 @@
+package test
+
+import (
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/AlexsanderHamir/PoolX/v2/pool"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// panicRecorder collects the panics reported to the pool's panic handler.
+type panicRecorder struct {
+	mu     sync.Mutex
+	events []pool.CallbackPanic
+}
+
+func (r *panicRecorder) record(event pool.CallbackPanic) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.events = append(r.events, event)
+}
+
+func (r *panicRecorder) callbacks() []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	names := make([]string, len(r.events))
+	for i, event := range r.events {
+		names[i] = event.Callback
+	}
+	return names
+}
+
+func TestCleanerPanicDiscardsObject(t *testing.T) {
+	recorder := &panicRecorder{}
+	config, err := pool.NewPoolConfigBuilder[*TestObject]().
+		SetInitialCapacity(4).
+		SetHardLimit(4).
+		SetMinShrinkCapacity(4).
+		SetPanicHandler(recorder.record).
+		Build()
+	require.NoError(t, err)
+
+	cleaner := func(obj *TestObject) {
+		if obj.Value < 0 {
+			panic("cannot clean a negative value")
+		}
+		obj.Value = 0
+	}
+
+	poolObj, err := pool.NewPool(config, func() *TestObject { return &TestObject{Value: 42} }, cleaner, nil)
+	require.NoError(t, err)
+	p := poolObj.(*pool.Pool[*TestObject])
+	defer func() {
+		require.NoError(t, p.Close())
+	}()
+
+	obj, err := p.Get()
+	require.NoError(t, err)
+
+	obj.Value = -1
+	assert.Error(t, p.Put(obj))
+
+	stats := p.GetPoolStatsSnapshot()
+	assert.Equal(t, uint64(1), stats.CallbackPanics)
+	assert.Equal(t, uint64(1), stats.DiscardedObjects)
+	assert.Equal(t, uint64(0), stats.ObjectsInUse)
+	assert.Equal(t, 1, stats.ObjectsDestroyed)
+	assert.Equal(t, []string{pool.CallbackCleaner}, recorder.callbacks())
+
+	objects := make([]*TestObject, 4)
+	for i := range objects {
+		objects[i], err = p.Get()
+		require.NoError(t, err, "the discarded object should have been replaced")
+		assert.NotSame(t, obj, objects[i])
+	}
+
+	for _, obj := range objects {
+		require.NoError(t, p.Put(obj))
+	}
+}
+
+func TestAllocatorPanicKeepsAccounting(t *testing.T) {
+	recorder := &panicRecorder{}
+	config, err := pool.NewPoolConfigBuilder[*TestObject]().
+		SetInitialCapacity(2).
+		SetHardLimit(8).
+		SetMinShrinkCapacity(2).
+		SetGrowthExponentialThresholdFactor(4).
+		SetAllocationStrategy(100, 2).
+		SetPanicHandler(recorder.record).
+		Build()
+	require.NoError(t, err)
+
+	var failing atomic.Bool
+	allocator := func() *TestObject {
+		if failing.Load() {
+			panic("allocation failed")
+		}
+		return &TestObject{Value: 42}
+	}
+	cleaner := func(obj *TestObject) {
+		obj.Value = 0
+	}
+
+	poolObj, err := pool.NewPool(config, allocator, cleaner, nil)
+	require.NoError(t, err)
+	p := poolObj.(*pool.Pool[*TestObject])
+	defer func() {
+		require.NoError(t, p.Close())
+	}()
+
+	objects := make([]*TestObject, 2)
+	for i := range objects {
+		objects[i], err = p.Get()
+		require.NoError(t, err)
+	}
+
+	createdBefore := p.GetPoolStatsSnapshot().ObjectsCreated
+	failing.Store(true)
+
+	require.NotPanics(t, func() {
+		obj, err := p.Get()
+		assert.Error(t, err)
+		assert.Nil(t, obj)
+	})
+
+	stats := p.GetPoolStatsSnapshot()
+	assert.Equal(t, createdBefore, stats.ObjectsCreated)
+	assert.Positive(t, stats.CallbackPanics)
+	assert.Contains(t, recorder.callbacks(), pool.CallbackAllocator)
+
+	failing.Store(false)
+	obj, err := p.Get()
+	require.NoError(t, err)
+	require.NoError(t, p.Put(obj))
+
+	for _, obj := range objects {
+		require.NoError(t, p.Put(obj))
+	}
+}
+
+func TestNewPoolWithPanickingAllocator(t *testing.T) {
+	allocator := func() *TestObject {
+		panic("backend unavailable")
+	}
+	cleaner := func(obj *TestObject) {}
+
+	var p pool.PoolObj[*TestObject]
+	var err error
+	require.NotPanics(t, func() {
+		p, err = pool.NewPool(nil, allocator, cleaner, nil)
+	})
+	assert.Error(t, err)
+	assert.Nil(t, p)
+}
+
+func TestSlabInitializerPanicFailsAllocation(t *testing.T) {
+	recorder := &panicRecorder{}
+	config, err := pool.NewPoolConfigBuilder[*TestObject]().
+		SetInitialCapacity(4).
+		SetHardLimit(4).
+		SetMinShrinkCapacity(4).
+		SetAllocationStrategy(100, 4).
+		SetSlabAllocation(true).
+		SetSlabInitializer(func(obj *TestObject) {
+			panic("cannot initialize")
+		}).
+		SetPanicHandler(recorder.record).
+		Build()
+	require.NoError(t, err)
+
+	cleaner := func(obj *TestObject) {}
+
+	var p pool.PoolObj[*TestObject]
+	require.NotPanics(t, func() {
+		p, err = pool.NewPool(config, func() *TestObject { return &TestObject{} }, cleaner, nil)
+	})
+	assert.Error(t, err)
+	assert.Nil(t, p)
+	assert.Equal(t, []string{pool.CallbackSlabInitializer}, recorder.callbacks())
+}
@@ -0,0 +1,234 @@
+package test
+
+import (
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/AlexsanderHamir/PoolX/v2/pool"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newCircuitBreakerPool creates a pool whose circuit opens after 3 failed allocations,
+// and takes its 4 initial objects so the next Get has to allocate.
+func newCircuitBreakerPool(t *testing.T, allocator func() *TestObject, openDuration time.Duration) (*pool.Pool[*TestObject], []*TestObject) {
+	cleaner := func(obj *TestObject) {
+		obj.Value = 0
+	}
+
+	config, err := pool.NewPoolConfigBuilder[*TestObject]().
+		SetInitialCapacity(4).
+		SetHardLimit(16).
+		SetMinShrinkCapacity(4).
+		SetGrowthExponentialThresholdFactor(4).
+		SetAllocationStrategy(100, 4).
+		SetCircuitBreaker(3, openDuration).
+		Build()
+	require.NoError(t, err)
+
+	poolObj, err := pool.NewPool(config, allocator, cleaner, nil)
+	require.NoError(t, err)
+	p := poolObj.(*pool.Pool[*TestObject])
+
+	objects := make([]*TestObject, 4)
+	for i := range objects {
+		objects[i], err = p.Get()
+		require.NoError(t, err)
+	}
+
+	return p, objects
+}
+
+// openCircuit calls Get until the failing allocator opens the circuit.
+func openCircuit(t *testing.T, p *pool.Pool[*TestObject]) {
+	for range 10 {
+		obj, err := p.Get()
+		require.Error(t, err)
+		assert.Nil(t, obj)
+		if errors.Is(err, pool.ErrCircuitOpen) {
+			return
+		}
+	}
+	require.Fail(t, "circuit never opened")
+}
+
+func TestCircuitBreakerOpensOnAllocatorPanics(t *testing.T) {
+	const openDuration = 100 * time.Millisecond
+
+	var (
+		failing atomic.Bool
+		calls   atomic.Int64
+	)
+
+	allocator := func() *TestObject {
+		calls.Add(1)
+		if failing.Load() {
+			panic("backend is down")
+		}
+		return &TestObject{Value: 42}
+	}
+	p, objects := newCircuitBreakerPool(t, allocator, openDuration)
+	defer func() {
+		require.NoError(t, p.Close())
+	}()
+
+	failing.Store(true)
+	openCircuit(t, p)
+
+	stats := p.GetPoolStatsSnapshot()
+	assert.Equal(t, "open", stats.CircuitState)
+	assert.Equal(t, 1, stats.CircuitTrips)
+	assert.GreaterOrEqual(t, stats.AllocationFailures, 3)
+
+	callsWhileOpen := calls.Load()
+	for range 5 {
+		_, err := p.Get()
+		assert.ErrorIs(t, err, pool.ErrCircuitOpen)
+	}
+	assert.Equal(t, callsWhileOpen, calls.Load(), "allocator called while the circuit is open")
+
+	require.NoError(t, p.Put(objects[0]))
+	obj, err := p.Get()
+	require.NoError(t, err, "idle objects are still handed out while the circuit is open")
+	objects[0] = obj
+
+	failing.Store(false)
+	time.Sleep(openDuration + 20*time.Millisecond)
+
+	obj, err = p.Get()
+	require.NoError(t, err)
+	require.NotNil(t, obj)
+	assert.Equal(t, "closed", p.GetPoolStatsSnapshot().CircuitState)
+
+	require.NoError(t, p.Put(obj))
+	for _, obj := range objects {
+		require.NoError(t, p.Put(obj))
+	}
+}
+
+func TestCircuitBreakerReopensWhenProbeFails(t *testing.T) {
+	const openDuration = 200 * time.Millisecond
+
+	var (
+		failing atomic.Bool
+		calls   atomic.Int64
+	)
+
+	allocator := func() *TestObject {
+		calls.Add(1)
+		if failing.Load() {
+			panic("backend is down")
+		}
+		return &TestObject{Value: 42}
+	}
+
+	p, objects := newCircuitBreakerPool(t, allocator, openDuration)
+	defer func() {
+		require.NoError(t, p.Close())
+	}()
+
+	failing.Store(true)
+	openCircuit(t, p)
+	require.Equal(t, 1, p.GetPoolStatsSnapshot().CircuitTrips)
+
+	// the probe fails once the open period is over, the circuit opens again for a whole period
+	time.Sleep(openDuration + 20*time.Millisecond)
+	callsBeforeProbe := calls.Load()
+
+	obj, err := p.Get()
+	require.Error(t, err)
+	assert.Nil(t, obj)
+	assert.Equal(t, callsBeforeProbe+1, calls.Load(), "a single probe allocation")
+
+	stats := p.GetPoolStatsSnapshot()
+	assert.Equal(t, "open", stats.CircuitState)
+	assert.Equal(t, 2, stats.CircuitTrips)
+
+	_, err = p.Get()
+	assert.ErrorIs(t, err, pool.ErrCircuitOpen)
+	assert.Equal(t, callsBeforeProbe+1, calls.Load(), "allocator called while the circuit is open")
+
+	for _, obj := range objects {
+		require.NoError(t, p.Put(obj))
+	}
+}
+
+func TestCircuitBreakerSingleProbeWhileHalfOpen(t *testing.T) {
+	const (
+		openDuration = 50 * time.Millisecond
+		callers      = 8
+	)
+
+	var (
+		failing atomic.Bool
+		probing atomic.Bool
+		probes  atomic.Int64
+		entered = make(chan struct{})
+		release = make(chan struct{})
+	)
+
+	// once armed, allocations count as probes and the first one blocks until release is closed
+	allocator := func() *TestObject {
+		if failing.Load() {
+			panic("backend is down")
+		}
+		if probing.Load() && probes.Add(1) == 1 {
+			close(entered)
+			<-release
+		}
+		return &TestObject{Value: 42}
+	}
+
+	p, objects := newCircuitBreakerPool(t, allocator, openDuration)
+	defer func() {
+		require.NoError(t, p.Close())
+	}()
+
+	failing.Store(true)
+	openCircuit(t, p)
+
+	failing.Store(false)
+	probing.Store(true)
+	time.Sleep(openDuration + 20*time.Millisecond)
+
+	var (
+		wg      sync.WaitGroup
+		mu      sync.Mutex
+		granted []*TestObject
+	)
+	for range callers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			// callers that find no idle object fail, the pool doesn't block
+			obj, err := p.Get()
+			if err != nil {
+				return
+			}
+			mu.Lock()
+			granted = append(granted, obj)
+			mu.Unlock()
+		}()
+	}
+
+	// the other callers get a chance to reach the allocator while the probe is in flight
+	<-entered
+	time.Sleep(20 * time.Millisecond)
+	assert.Equal(t, int64(1), probes.Load(), "only the probe reaches the allocator while the circuit is half-open")
+
+	close(release)
+	wg.Wait()
+
+	stats := p.GetPoolStatsSnapshot()
+	assert.Equal(t, "closed", stats.CircuitState)
+	assert.Equal(t, 1, stats.CircuitTrips)
+	assert.NotEmpty(t, granted, "the probe's caller gets an object")
+
+	for _, obj := range append(objects, granted...) {
+		require.NoError(t, p.Put(obj))
+	}
+}
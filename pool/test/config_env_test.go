@@ -0,0 +1,89 @@
+package test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/AlexsanderHamir/PoolX/v2/pool"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestApplyEnv(t *testing.T) {
+	t.Setenv("POOLX_ORDERS_API_HARD_LIMIT", "2048")
+	t.Setenv("POOLX_ORDERS_API_INITIAL_CAPACITY", "256")
+	t.Setenv("POOLX_ORDERS_API_SHRINK_AGGRESSIVENESS", "very_aggressive")
+	t.Setenv("POOLX_ORDERS_API_RING_BUFFER_TIMEOUT", "750ms")
+	t.Setenv("POOLX_HARD_LIMIT", "1") // belongs to unnamed pools
+
+	builder, err := pool.NewPoolConfigBuilder[*TestObject]().
+		SetName("orders-api").
+		SetPoolBasicConfigs(128, 1024, true).
+		ApplyEnv("POOLX")
+	require.NoError(t, err)
+
+	config, err := builder.Build()
+	require.NoError(t, err)
+
+	spec := config.Spec()
+	assert.Equal(t, 2048, spec.HardLimit)
+	assert.Equal(t, 256, spec.InitialCapacity)
+	assert.Equal(t, pool.AggressivenessVeryAggressive, spec.Shrink.AggressivenessLevel)
+	assert.Equal(t, pool.AggressivenessVeryAggressive, spec.FastPath.Shrink.AggressivenessLevel)
+	assert.Equal(t, 750*time.Millisecond, spec.RingBuffer.RTimeout)
+	assert.Equal(t, 750*time.Millisecond, spec.RingBuffer.WTimeout)
+}
+
+func TestApplyEnvUnnamedPool(t *testing.T) {
+	t.Setenv("POOLX_HARD_LIMIT", "4096")
+	t.Setenv("POOLX_SHRINK_AGGRESSIVENESS", "1")
+	t.Setenv("POOLX_INITIAL_CAPACITY", "")
+
+	builder, err := pool.NewPoolConfigBuilder[*TestObject]().ApplyEnv("POOLX")
+	require.NoError(t, err)
+
+	config, err := builder.Build()
+	require.NoError(t, err)
+	assert.Equal(t, 4096, config.GetHardLimit())
+	assert.Equal(t, 64, config.GetInitialCapacity(), "empty variables are ignored")
+	assert.Equal(t, pool.AggressivenessConservative, config.Spec().Shrink.AggressivenessLevel)
+}
+
+func TestApplyEnvMalformed(t *testing.T) {
+	t.Setenv("POOLX_CACHE_HARD_LIMIT", "lots")
+	t.Setenv("POOLX_CACHE_INITIAL_CAPACITY", "64")
+	t.Setenv("POOLX_CACHE_SHRINK_AGGRESSIVENESS", "lazy")
+	t.Setenv("POOLX_CACHE_RING_BUFFER_TIMEOUT", "-1s")
+
+	builder := pool.NewPoolConfigBuilder[*TestObject]().SetName("cache").SetInitialCapacity(128)
+	_, err := builder.ApplyEnv("POOLX")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `POOLX_CACHE_HARD_LIMIT: invalid hardLimit "lots"`)
+	assert.Contains(t, err.Error(), `POOLX_CACHE_SHRINK_AGGRESSIVENESS: invalid aggressivenessLevel "lazy"`)
+	assert.Contains(t, err.Error(), `POOLX_CACHE_RING_BUFFER_TIMEOUT: invalid ringBufferConfig timeout "-1s"`)
+	assert.NotContains(t, err.Error(), "INITIAL_CAPACITY")
+
+	config, err := builder.Build()
+	require.NoError(t, err)
+	assert.Equal(t, 128, config.GetInitialCapacity(), "a rejected environment leaves the builder untouched")
+}
+
+func TestApplyEnvValidatedByBuild(t *testing.T) {
+	t.Setenv("POOLX_HARD_LIMIT", "16")
+
+	builder, err := pool.NewPoolConfigBuilder[*TestObject]().ApplyEnv("POOLX")
+	require.NoError(t, err)
+
+	_, err = builder.Build()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "hardLimit (16) must be >= initialCapacity (64)")
+}
+
+func TestApplyEnvEnforcedCustomConfig(t *testing.T) {
+	t.Setenv("POOLX_SHRINK_AGGRESSIVENESS", "balanced")
+
+	_, err := pool.NewPoolConfigBuilder[*TestObject]().EnforceCustomConfig().ApplyEnv("POOLX")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "POOLX_SHRINK_AGGRESSIVENESS: cannot set aggressivenessLevel")
+}
@@ -0,0 +1,116 @@
+package test
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/AlexsanderHamir/PoolX/v2/pool"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConfigJSONRoundTrip(t *testing.T) {
+	builder, err := pool.NewPoolConfigBuilder[*TestObject]().
+		SetName("orders").
+		SetPoolBasicConfigs(128, 1024, true).
+		SetMaxWaiters(16).
+		SetQueueTimeBudget(250*time.Millisecond).
+		SetCircuitBreaker(3, 2*time.Second).
+		SetStatsHistory(time.Second, 60).
+		SetShrinkAggressiveness(pool.AggressivenessAggressive)
+	require.NoError(t, err)
+
+	config, err := builder.Build()
+	require.NoError(t, err)
+
+	data, err := json.Marshal(config)
+	require.NoError(t, err)
+
+	var raw map[string]any
+	require.NoError(t, json.Unmarshal(data, &raw))
+	assert.Equal(t, "250ms", raw["queue_time_budget"])
+	assert.Equal(t, "2s", raw["circuit_breaker_open_duration"])
+	shrink := raw["shrink"].(map[string]any)
+	assert.Equal(t, "aggressive", shrink["aggressiveness_level"])
+	assert.IsType(t, "", shrink["check_interval"])
+
+	decoded := &pool.PoolConfig[*TestObject]{}
+	require.NoError(t, json.Unmarshal(data, decoded))
+	assert.Equal(t, config.Spec(), decoded.Spec())
+
+	p := createTestPool(t, decoded)
+	require.NoError(t, p.Close())
+}
+
+func TestParseConfigSpecDefaults(t *testing.T) {
+	spec, err := pool.ParseConfigSpec([]byte(`{
+		"initial_capacity": 128,
+		"hard_limit": 512,
+		"shrink": {"aggressiveness_level": "very_aggressive", "shrink_cooldown": "90s"},
+		"ring_buffer": {"block": true, "read_timeout": "1s"}
+	}`))
+	require.NoError(t, err)
+
+	builder, err := pool.NewPoolConfigBuilder[*TestObject]().
+		SetPoolBasicConfigs(128, 512, true).
+		SetShrinkAggressiveness(pool.AggressivenessVeryAggressive)
+	require.NoError(t, err)
+	defaults, err := builder.Build()
+	require.NoError(t, err)
+
+	expected := defaults.Spec()
+	expected.Shrink.ShrinkCooldown = 90 * time.Second
+	expected.RingBuffer.Block = true
+	expected.RingBuffer.RTimeout = time.Second
+	assert.Equal(t, expected, spec)
+
+	config, err := pool.NewPoolConfigFromSpec[*TestObject](spec)
+	require.NoError(t, err)
+	assert.Equal(t, 512, config.GetHardLimit())
+}
+
+func TestConfigJSONInvalid(t *testing.T) {
+	tests := []struct {
+		name string
+		data string
+		err  string
+	}{
+		{"malformed", `{"hard_limit": `, "unexpected end of JSON input"},
+		{"wrong type", `{"hard_limit": "many"}`, "invalid pool configuration"},
+		{"unknown aggressiveness", `{"shrink": {"aggressiveness_level": "lazy"}}`, `unknown aggressiveness level "lazy"`},
+		{"bad duration", `{"queue_time_budget": "soon"}`, `invalid duration "soon"`},
+		{"numeric duration", `{"queue_time_budget": 1500000000}`, `invalid duration 1500000000, must be a string`},
+		{"hard limit below capacity", `{"initial_capacity": 128, "hard_limit": 64}`, "hardLimit (64) must be >= initialCapacity (128)"},
+		{"negative waiters", `{"max_waiters": -1}`, "maxWaiters must be >= 0"},
+		{"zero check interval", `{"shrink": {"check_interval": "0s"}}`, "checkInterval must be greater than 0"},
+		{"custom config without values", `{"shrink": {"enforce_custom_config": true}}`, "shrink configuration validation failed"},
+		{"half stats history", `{"stats_history_interval": "1s"}`, "statsHistorySize (0) must be both greater than 0 or both 0"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config, err := pool.NewPoolConfigBuilder[*TestObject]().SetMaxWaiters(4).Build()
+			require.NoError(t, err)
+			before := config.Spec()
+
+			err = json.Unmarshal([]byte(tt.data), config)
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.err)
+			assert.Equal(t, before, config.Spec(), "a rejected configuration leaves the config untouched")
+		})
+	}
+}
+
+func TestAggressivenessLevelNames(t *testing.T) {
+	for level := pool.AggressivenessDisabled; level <= pool.AggressivenessExtreme; level++ {
+		parsed, err := pool.ParseAggressivenessLevel(level.String())
+		require.NoError(t, err)
+		assert.Equal(t, level, parsed)
+	}
+
+	assert.Equal(t, "AggressivenessLevel(9)", pool.AggressivenessLevel(9).String())
+	_, err := json.Marshal(pool.ShrinkSpec{AggressivenessLevel: 9})
+	assert.Error(t, err)
+}
@@ -0,0 +1,148 @@
+package test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/AlexsanderHamir/PoolX/v2/pool"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type reloadResult struct {
+	reconfigured []string
+	err          error
+}
+
+// newWatchedRegistry registers the pools orders and sessions, and returns the path of their config file.
+func newWatchedRegistry(t *testing.T) (*pool.Registry, string) {
+	r := pool.NewRegistry()
+	for _, name := range []string{"orders", "sessions"} {
+		p := createNamedPool(t, name)
+		t.Cleanup(func() { p.Close() })
+		require.NoError(t, r.Register(p, nil))
+	}
+
+	return r, filepath.Join(t.TempDir(), "pools.json")
+}
+
+// writeConfig replaces the file atomically, so the watcher never reads a partial write.
+func writeConfig(t *testing.T, path, data string) {
+	tmp := path + ".tmp"
+	require.NoError(t, os.WriteFile(tmp, []byte(data), 0o600))
+	require.NoError(t, os.Rename(tmp, path))
+}
+
+func hardLimit(t *testing.T, r *pool.Registry, name string) int {
+	entry, ok := r.Lookup(name)
+	require.True(t, ok)
+	return entry.Pool.GetConfigSpec().HardLimit
+}
+
+func TestConfigWatcherReload(t *testing.T) {
+	r, path := newWatchedRegistry(t)
+	writeConfig(t, path, `{
+		"orders": {"hard_limit": 4096, "shrink": {"aggressiveness_level": "conservative", "shrink_percent": 15}},
+		"sessions": {}
+	}`)
+
+	reconfigured, err := pool.NewConfigWatcher(path, r).Reload()
+	require.NoError(t, err)
+	assert.Equal(t, []string{"orders"}, reconfigured, "unchanged pools are left out")
+
+	entry, _ := r.Lookup("orders")
+	spec := entry.Pool.GetConfigSpec()
+	assert.Equal(t, 4096, spec.HardLimit)
+	assert.Equal(t, pool.AggressivenessConservative, spec.Shrink.AggressivenessLevel)
+	assert.Equal(t, 15*time.Minute, spec.Shrink.CheckInterval, "missing parameters take the values of the new level")
+	assert.Equal(t, 15, spec.Shrink.ShrinkPercent)
+}
+
+func TestConfigWatcherRejectsInvalidFile(t *testing.T) {
+	tests := []struct {
+		name string
+		data string
+		err  string
+	}{
+		{"malformed", `{"orders": `, "rejected"},
+		{"unknown pool", `{"orders": {"hard_limit": 4096}, "invoices": {}}`, `pool "invoices": not registered`},
+		{"invalid value", `{"orders": {"hard_limit": 4096}, "sessions": {"hard_limit": 8}}`, "hardLimit (8) must be >= initialCapacity (64)"},
+		{"bad duration", `{"orders": {"shrink": {"check_interval": "often"}}}`, `invalid duration "often"`},
+		{"fixed parameter", `{"orders": {"initial_capacity": 128}}`, "initial_capacity can't be changed on a running pool"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, path := newWatchedRegistry(t)
+			writeConfig(t, path, tt.data)
+
+			reconfigured, err := pool.NewConfigWatcher(path, r).Reload()
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.err)
+			assert.Empty(t, reconfigured)
+			assert.Equal(t, 10_000, hardLimit(t, r, "orders"), "no pool is changed")
+		})
+	}
+}
+
+func TestConfigWatcherPolling(t *testing.T) {
+	r, path := newWatchedRegistry(t)
+	writeConfig(t, path, `{"orders": {"hard_limit": 2048}}`)
+
+	results := make(chan reloadResult, 16)
+	watcher := pool.NewConfigWatcher(path, r).OnReload(func(reconfigured []string, err error) {
+		results <- reloadResult{reconfigured, err}
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go watcher.Watch(ctx, 5*time.Millisecond)
+
+	result := <-results
+	require.NoError(t, result.err)
+	assert.Equal(t, 2048, hardLimit(t, r, "orders"))
+
+	writeConfig(t, path, `{"orders": {"hard_limit": -1}}`)
+	result = <-results
+	require.Error(t, result.err)
+	assert.Equal(t, 2048, hardLimit(t, r, "orders"))
+
+	time.Sleep(30 * time.Millisecond)
+	assert.Empty(t, results, "an unchanged rejected file is reported once")
+
+	writeConfig(t, path, `{"orders": {"hard_limit": 3072}}`)
+	result = <-results
+	require.NoError(t, result.err)
+	assert.Equal(t, []string{"orders"}, result.reconfigured)
+	assert.Equal(t, 3072, hardLimit(t, r, "orders"))
+}
+
+func TestConfigWatcherSignal(t *testing.T) {
+	r, path := newWatchedRegistry(t)
+	writeConfig(t, path, `{}`)
+
+	results := make(chan reloadResult, 16)
+	watcher := pool.NewConfigWatcher(path, r).OnReload(func(reconfigured []string, err error) {
+		results <- reloadResult{reconfigured, err}
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go watcher.Watch(ctx, 0, syscall.SIGHUP)
+
+	// the signal is registered before the file is first applied
+	require.NoError(t, (<-results).err)
+
+	writeConfig(t, path, `{"sessions": {"hard_limit": 512}}`)
+	require.NoError(t, syscall.Kill(os.Getpid(), syscall.SIGHUP))
+
+	result := <-results
+	require.NoError(t, result.err)
+	assert.Equal(t, []string{"sessions"}, result.reconfigured)
+	assert.Equal(t, 512, hardLimit(t, r, "sessions"))
+}
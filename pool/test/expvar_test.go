@@ -0,0 +1,79 @@
+package test
+
+import (
+	"encoding/json"
+	"expvar"
+	"sync"
+	"testing"
+
+	"github.com/AlexsanderHamir/PoolX/v2/pool"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPublishExpvar(t *testing.T) {
+	config, err := pool.NewPoolConfigBuilder[*TestObject]().
+		SetPoolBasicConfigs(64, 256, true).
+		SetMaxWaiters(8).
+		Build()
+	require.NoError(t, err)
+
+	p := createTestPool(t, config)
+	defer func() {
+		require.NoError(t, p.Close())
+	}()
+
+	obj, err := p.Get()
+	require.NoError(t, err)
+
+	require.NoError(t, pool.PublishExpvar("poolx_test_expvar", p))
+	assert.Error(t, pool.PublishExpvar("poolx_test_expvar", p), "names can only be published once")
+
+	v := expvar.Get("poolx_test_expvar")
+	require.NotNil(t, v)
+
+	var published struct {
+		Stats  pool.PoolStatsSnapshot `json:"stats"`
+		Config pool.ConfigSpec        `json:"config"`
+	}
+	require.NoError(t, json.Unmarshal([]byte(v.String()), &published))
+
+	assert.Equal(t, uint64(1), published.Stats.TotalGets)
+	assert.Equal(t, uint64(1), published.Stats.ObjectsInUse)
+	assert.Equal(t, 64, published.Config.InitialCapacity)
+	assert.Equal(t, 256, published.Config.HardLimit)
+	assert.Equal(t, 8, published.Config.MaxWaiters)
+	assert.Equal(t, config.Spec(), published.Config)
+
+	require.NoError(t, p.Put(obj))
+}
+
+func TestPublishExpvarConcurrently(t *testing.T) {
+	p := createTestPool(t, createHardLimitTestConfig(t, true))
+	defer func() {
+		require.NoError(t, p.Close())
+	}()
+
+	const publishers = 16
+	errs := make(chan error, publishers)
+
+	var wg sync.WaitGroup
+	for range publishers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			errs <- pool.PublishExpvar("poolx_test_expvar_concurrent", p)
+		}()
+	}
+	wg.Wait()
+	close(errs)
+
+	var published int
+	for err := range errs {
+		if err == nil {
+			published++
+		}
+	}
+	assert.Equal(t, 1, published, "a name is published once, the other callers get an error")
+}
@@ -0,0 +1,286 @@
+package test
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/AlexsanderHamir/PoolX/v2/pool"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetAtomicReservesUntilComplete(t *testing.T) {
+	p, objects := createExhaustedPool(t, 6, func(b pool.PoolConfigBuilder[*TestObject]) pool.PoolConfigBuilder[*TestObject] {
+		return b
+	})
+	defer func() {
+		require.NoError(t, p.Close())
+	}()
+
+	done := make(chan []*TestObject, 1)
+	go func() {
+		objs, err := p.GetAtomic(context.Background(), 3)
+		assert.NoError(t, err)
+		done <- objs
+	}()
+	waitForWaiters(t, p, 1)
+
+	for i := range 2 {
+		require.NoError(t, p.Put(objects[i]))
+	}
+
+	select {
+	case <-done:
+		t.Fatal("GetAtomic returned before all objects were available")
+	case <-time.After(50 * time.Millisecond):
+	}
+	assert.Equal(t, 2, p.GetPoolStatsSnapshot().Reserved)
+
+	require.NoError(t, p.Put(objects[2]))
+
+	select {
+	case objs := <-done:
+		require.Len(t, objs, 3)
+		for _, obj := range objs {
+			assert.NotNil(t, obj)
+			require.NoError(t, p.Put(obj))
+		}
+	case <-time.After(time.Second):
+		t.Fatal("GetAtomic was not served after all objects were returned")
+	}
+
+	for _, obj := range objects[3:] {
+		require.NoError(t, p.Put(obj))
+	}
+
+	stats := p.GetPoolStatsSnapshot()
+	assert.Equal(t, 0, stats.Reserved)
+	assert.Equal(t, uint64(0), stats.ObjectsInUse)
+	assert.Equal(t, uint64(3), stats.WaitQueueHandoffs)
+	assert.Equal(t, stats.TotalGets, stats.TotalReturns())
+}
+
+func TestGetAtomicNoDeadlockAtHardLimit(t *testing.T) {
+	const (
+		hardLimit  = 6
+		perJob     = 3
+		numWorkers = 4
+		numJobs    = 50
+	)
+
+	config, err := pool.NewPoolConfigBuilder[*TestObject]().
+		SetInitialCapacity(2).
+		SetHardLimit(hardLimit).
+		SetMinShrinkCapacity(2).
+		SetGrowthExponentialThresholdFactor(4).
+		SetRingBufferBlocking(true).
+		SetRingBufferTimeout(5 * time.Second).
+		Build()
+	require.NoError(t, err)
+
+	p := createTestPool(t, config)
+	defer func() {
+		require.NoError(t, p.Close())
+	}()
+
+	var wg sync.WaitGroup
+	for range numWorkers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for range numJobs {
+				objs, err := p.GetAtomic(context.Background(), perJob)
+				if !assert.NoError(t, err) {
+					return
+				}
+				assert.Len(t, objs, perJob)
+				for _, obj := range objs {
+					assert.NoError(t, p.Put(obj))
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	stats := p.GetPoolStatsSnapshot()
+	assert.Equal(t, uint64(numWorkers*numJobs*perJob), stats.TotalGets)
+	assert.Equal(t, uint64(0), stats.ObjectsInUse)
+	assert.LessOrEqual(t, stats.ObjectsCreated-stats.ObjectsDestroyed, hardLimit)
+}
+
+func TestGetAtomicGrowsPool(t *testing.T) {
+	config, err := pool.NewPoolConfigBuilder[*TestObject]().
+		SetInitialCapacity(2).
+		SetHardLimit(8).
+		SetMinShrinkCapacity(2).
+		SetGrowthExponentialThresholdFactor(4).
+		SetRingBufferBlocking(true).
+		Build()
+	require.NoError(t, err)
+
+	p := createTestPool(t, config)
+	defer func() {
+		require.NoError(t, p.Close())
+	}()
+
+	objs, err := p.GetAtomic(context.Background(), 8)
+	require.NoError(t, err)
+	require.Len(t, objs, 8)
+
+	for _, obj := range objs {
+		require.NoError(t, p.Put(obj))
+	}
+}
+
+func TestGetAtomicCancelReleasesReservation(t *testing.T) {
+	p, objects := createExhaustedPool(t, 4, func(b pool.PoolConfigBuilder[*TestObject]) pool.PoolConfigBuilder[*TestObject] {
+		return b
+	})
+	defer func() {
+		require.NoError(t, p.Close())
+	}()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() {
+		_, err := p.GetAtomic(ctx, 3)
+		done <- err
+	}()
+	waitForWaiters(t, p, 1)
+
+	require.NoError(t, p.Put(objects[0]))
+	assert.Equal(t, 1, p.GetPoolStatsSnapshot().Reserved)
+
+	cancel()
+	select {
+	case err := <-done:
+		assert.ErrorIs(t, err, context.Canceled)
+	case <-time.After(time.Second):
+		t.Fatal("GetAtomic did not return after ctx was canceled")
+	}
+
+	stats := p.GetPoolStatsSnapshot()
+	assert.Equal(t, 0, stats.Reserved)
+	assert.Equal(t, 0, stats.Waiters)
+
+	obj, err := p.GetPriority(context.Background(), 0)
+	require.NoError(t, err)
+	require.NoError(t, p.Put(obj))
+
+	for _, obj := range objects[1:] {
+		require.NoError(t, p.Put(obj))
+	}
+}
+
+func TestGetAtomicCloseDestroysReservation(t *testing.T) {
+	listener := &recordingListener{}
+	config, err := pool.NewPoolConfigBuilder[*TestObject]().
+		SetInitialCapacity(2).
+		SetHardLimit(2).
+		SetMinShrinkCapacity(2).
+		SetRingBufferBlocking(true).
+		SetAllocationStrategy(100, 2).
+		SetListener(listener).
+		Build()
+	require.NoError(t, err)
+
+	allocator := func() *TestObject {
+		return &TestObject{Value: 42}
+	}
+	// the cleaner panics on objects marked with -1, so they are discarded instead of handed to the waiter
+	cleaner := func(obj *TestObject) {
+		if obj.Value == -1 {
+			panic("corrupted object")
+		}
+		obj.Value = 0
+	}
+
+	poolObj, err := pool.NewPool(config, allocator, cleaner, nil)
+	require.NoError(t, err)
+	p := poolObj.(*pool.Pool[*TestObject])
+
+	objects := make([]*TestObject, 2)
+	for i := range objects {
+		objects[i], err = p.Get()
+		require.NoError(t, err)
+	}
+
+	done := make(chan error, 1)
+	go func() {
+		_, err := p.GetAtomic(context.Background(), 2)
+		done <- err
+	}()
+	waitForWaiters(t, p, 1)
+
+	require.NoError(t, p.Put(objects[0]))
+	objects[1].Value = -1
+	require.Error(t, p.Put(objects[1]))
+	require.NoError(t, p.Close())
+
+	select {
+	case err := <-done:
+		assert.Error(t, err)
+	case <-time.After(time.Second):
+		t.Fatal("GetAtomic did not return after the pool was closed")
+	}
+
+	stats := p.GetPoolStatsSnapshot()
+	assert.Equal(t, 2, stats.ObjectsDestroyed, "the discarded object and the reserved one")
+	assert.Equal(t, uint64(1), stats.WaitQueueHandoffs)
+
+	listener.mu.Lock()
+	defer listener.mu.Unlock()
+	assert.Equal(t, 2, listener.destroyed)
+}
+
+func TestGetAtomicNonBlocking(t *testing.T) {
+	p, objects := createExhaustedPool(t, 4, func(b pool.PoolConfigBuilder[*TestObject]) pool.PoolConfigBuilder[*TestObject] {
+		return b.SetRingBufferBlocking(false)
+	})
+	defer func() {
+		require.NoError(t, p.Close())
+	}()
+
+	require.NoError(t, p.Put(objects[0]))
+
+	// one object is idle, the caller doesn't wait for the second one
+	done := make(chan error, 1)
+	go func() {
+		objs, err := p.GetAtomic(context.Background(), 2)
+		assert.Nil(t, objs)
+		done <- err
+	}()
+
+	select {
+	case err := <-done:
+		assert.Error(t, err)
+	case <-time.After(time.Second):
+		t.Fatal("GetAtomic waited on a non-blocking pool")
+	}
+	assert.Equal(t, 0, p.GetPoolStatsSnapshot().Waiters)
+
+	// the reserved object went back to the pool
+	obj, err := p.Get()
+	require.NoError(t, err)
+	require.NoError(t, p.Put(obj))
+
+	for _, obj := range objects[1:] {
+		require.NoError(t, p.Put(obj))
+	}
+}
+
+func TestGetAtomicInvalidCount(t *testing.T) {
+	p := createTestPool(t, createHardLimitTestConfig(t, true))
+	defer func() {
+		require.NoError(t, p.Close())
+	}()
+
+	for _, n := range []int{0, -1, 1000} {
+		objs, err := p.GetAtomic(context.Background(), n)
+		assert.Error(t, err, "n=%d", n)
+		assert.Nil(t, objs)
+	}
+}
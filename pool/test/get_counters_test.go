@@ -0,0 +1,48 @@
+package test
+
+import (
+	"testing"
+
+	"github.com/AlexsanderHamir/PoolX/v2/pool"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetPathCounters(t *testing.T) {
+	config, err := pool.NewPoolConfigBuilder[*TestObject]().
+		SetInitialCapacity(10).
+		SetHardLimit(10).
+		SetMinShrinkCapacity(10).
+		SetFastPathBasicConfigs(10, 1, 1, 50, 20).
+		SetAllocationStrategy(50, 5).
+		Build()
+	require.NoError(t, err)
+
+	p := createTestPool(t, config)
+
+	var objects []*TestObject
+	for range 12 {
+		if obj, err := p.Get(); err == nil {
+			objects = append(objects, obj)
+		}
+	}
+	require.Len(t, objects, 10)
+
+	stats := p.GetPoolStatsSnapshot()
+
+	// The 6th Get finds L1 empty and creates 5 objects on demand, the next ones hit L1 again.
+	assert.Equal(t, uint64(9), stats.L1Hits)
+	assert.Equal(t, uint64(5), stats.OnDemandCreations)
+	assert.Positive(t, stats.RefillAttempts)
+	assert.Equal(t, stats.RefillAttempts, stats.RefillSuccesses+stats.RefillFailures)
+	assert.Positive(t, stats.RefillGrowthBlocked)
+	assert.LessOrEqual(t, stats.RefillGrowthBlocked, stats.RefillFailures)
+	assert.Equal(t, uint64(2), stats.SlowPathGets, "the Gets beyond the hard limit")
+	assert.Equal(t, uint64(8), stats.SlowPathRetries)
+
+	for _, obj := range objects {
+		require.NoError(t, p.Put(obj))
+	}
+	require.NoError(t, p.Close())
+}
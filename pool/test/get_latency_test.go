@@ -0,0 +1,109 @@
+package test
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/AlexsanderHamir/PoolX/v2/pool"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetLatencyHistogramsByPath(t *testing.T) {
+	config, err := pool.NewPoolConfigBuilder[*TestObject]().
+		SetInitialCapacity(10).
+		SetHardLimit(10).
+		SetMinShrinkCapacity(10).
+		SetGetLatencyHistograms(true).
+		Build()
+	require.NoError(t, err)
+
+	p := createTestPool(t, config)
+
+	const attempts = 15
+	var objects []*TestObject
+	for range attempts {
+		obj, err := p.Get()
+		if err == nil {
+			objects = append(objects, obj)
+		}
+	}
+
+	require.Len(t, objects, 10)
+
+	stats := p.GetExtendedStatsSnapshot()
+	require.NotNil(t, stats.GetLatency)
+
+	var total uint64
+	for _, latency := range stats.GetLatency {
+		total += latency.Count
+	}
+	assert.Equal(t, uint64(attempts), total)
+	assert.Positive(t, stats.GetLatency[pool.GetPathL1Hit.String()].Count)
+	assert.Equal(t, uint64(attempts-len(objects)), stats.GetLatency[pool.GetPathFailed.String()].Count)
+
+	encoded, err := json.Marshal(stats)
+	require.NoError(t, err)
+	assert.Contains(t, string(encoded), `"total_gets":10`)
+	assert.Contains(t, string(encoded), `"get_latency":{`)
+
+	for _, obj := range objects {
+		require.NoError(t, p.Put(obj))
+	}
+	require.NoError(t, p.Close())
+}
+
+func TestGetLatencyHistogramsGetPriority(t *testing.T) {
+	config, err := pool.NewPoolConfigBuilder[*TestObject]().
+		SetGetLatencyHistograms(true).
+		Build()
+	require.NoError(t, err)
+
+	p := createTestPool(t, config)
+	defer func() {
+		require.NoError(t, p.Close())
+	}()
+
+	obj, err := p.GetPriority(context.Background(), 1)
+	require.NoError(t, err)
+	require.NoError(t, p.Put(obj))
+
+	stats := p.GetExtendedStatsSnapshot()
+	assert.Equal(t, uint64(1), stats.GetLatency[pool.GetPathL1Hit.String()].Count)
+	assert.Equal(t, uint64(1), stats.L1Hits)
+}
+
+func TestGetLatencyHistogramsDisabledByDefault(t *testing.T) {
+	p := createTestPool(t, nil)
+	defer func() {
+		require.NoError(t, p.Close())
+	}()
+
+	obj, err := p.Get()
+	require.NoError(t, err)
+	require.NoError(t, p.Put(obj))
+
+	assert.Nil(t, p.GetExtendedStatsSnapshot().GetLatency)
+}
+
+func TestLatencySnapshotQuantile(t *testing.T) {
+	snapshot := pool.LatencySnapshot{
+		Count: 10,
+		Sum:   100 * time.Microsecond,
+		Max:   40 * time.Microsecond,
+		Buckets: []pool.LatencyBucket{
+			{UpperBound: time.Microsecond, Count: 5},
+			{UpperBound: 10 * time.Microsecond, Count: 4},
+			{UpperBound: 50 * time.Microsecond, Count: 1},
+		},
+	}
+
+	assert.Equal(t, 10*time.Microsecond, snapshot.Mean())
+	assert.Equal(t, time.Microsecond, snapshot.Quantile(0.5))
+	assert.Equal(t, 10*time.Microsecond, snapshot.Quantile(0.9))
+	assert.Equal(t, 40*time.Microsecond, snapshot.Quantile(0.99), "capped at the max")
+	assert.Zero(t, pool.LatencySnapshot{}.Quantile(0.5))
+}
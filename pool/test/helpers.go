@@ -3,6 +3,8 @@ package test
 import (
 	"context"
 	"fmt"
+	"io"
+	"log/slog"
 	"math/rand"
 	"sync"
 	"testing"
@@ -106,6 +108,7 @@ func storeDefaultConfigValues(config *pool.PoolConfig[*TestObject]) DefaultConfi
 		FastPathShrinkAggressiveness:       config.GetFastPath().GetShrink().GetAggressivenessLevel(),
 		FastPathShrinkPercent:              config.GetFastPath().GetShrink().GetShrinkPercent(),
 		FastPathShrinkMinCapacity:          config.GetFastPath().GetShrink().GetMinCapacity(),
+		Verbose:                            config.GetLogger() != nil,
 		RingBufferBlocking:                 config.GetRingBufferConfig().IsBlocking(),
 		ReadTimeout:                        config.GetRingBufferConfig().GetReadTimeout(),
 		WriteTimeout:                       config.GetRingBufferConfig().GetWriteTimeout(),
@@ -141,6 +144,7 @@ func createCustomConfig(t *testing.T) *pool.PoolConfig[*TestObject] {
 		SetFastPathShrinkMinCapacity(20121).
 		SetRingBufferBlocking(true).
 		SetRingBufferTimeout(5 * time.Second).
+		SetLogger(slog.New(slog.NewTextHandler(io.Discard, nil))).
 		Build()
 	require.NoError(t, err)
 
@@ -173,6 +177,7 @@ func verifyCustomValuesDifferent(t *testing.T, original DefaultConfigValues, cus
 	assert.NotEqual(t, original.FastPathShrinkAggressiveness, custom.GetFastPath().GetShrink().GetAggressivenessLevel())
 	assert.NotEqual(t, original.FastPathShrinkPercent, custom.GetFastPath().GetShrink().GetShrinkPercent())
 	assert.NotEqual(t, original.FastPathShrinkMinCapacity, custom.GetFastPath().GetShrink().GetMinCapacity())
+	assert.NotEqual(t, original.Verbose, custom.GetLogger() != nil)
 	assert.NotEqual(t, original.RingBufferBlocking, custom.GetRingBufferConfig().IsBlocking())
 	assert.NotEqual(t, original.ReadTimeout, custom.GetRingBufferConfig().GetReadTimeout())
 	assert.NotEqual(t, original.WriteTimeout, custom.GetRingBufferConfig().GetWriteTimeout())
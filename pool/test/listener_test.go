@@ -0,0 +1,200 @@
+package test
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/AlexsanderHamir/PoolX/v2/pool"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// recordingListener counts the lifecycle events it receives.
+type recordingListener struct {
+	mu sync.Mutex
+
+	grows, shrinks, l1Resizes int
+	allocated, destroyed      int
+	growthBlocked             int
+	waitStarts, waitEnds      int
+	lastGrow                  [2]int
+}
+
+func (l *recordingListener) OnGrow(oldCapacity, newCapacity int) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.grows++
+	l.lastGrow = [2]int{oldCapacity, newCapacity}
+}
+
+func (l *recordingListener) OnShrink(oldCapacity, newCapacity, destroyed int) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.shrinks++
+}
+
+func (l *recordingListener) OnL1Resize(oldCapacity, newCapacity int) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.l1Resizes++
+}
+
+func (l *recordingListener) OnAllocate(count int) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.allocated += count
+}
+
+func (l *recordingListener) OnDestroy(count int) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.destroyed += count
+}
+
+func (l *recordingListener) OnGrowthBlocked() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.growthBlocked++
+}
+
+func (l *recordingListener) OnWaitStart() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.waitStarts++
+}
+
+func (l *recordingListener) OnWaitEnd(waited time.Duration) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.waitEnds++
+}
+
+func TestListenerGrowthEvents(t *testing.T) {
+	listener := &recordingListener{}
+	config, err := pool.NewPoolConfigBuilder[*TestObject]().
+		SetInitialCapacity(2).
+		SetHardLimit(6).
+		SetMinShrinkCapacity(2).
+		SetGrowthExponentialThresholdFactor(4).
+		SetAllocationStrategy(100, 2).
+		SetFastPathGrowthEventsTrigger(1).
+		SetListener(listener).
+		Build()
+	require.NoError(t, err)
+
+	p := createTestPool(t, config)
+	defer func() {
+		require.NoError(t, p.Close())
+	}()
+
+	objects := make([]*TestObject, 6)
+	for i := range objects {
+		objects[i], err = p.Get()
+		require.NoError(t, err)
+	}
+
+	obj, err := p.Get()
+	assert.Error(t, err)
+	assert.Nil(t, obj)
+
+	stats := p.GetPoolStatsSnapshot()
+
+	listener.mu.Lock()
+	assert.Equal(t, stats.TotalGrowthEvents, listener.grows)
+	assert.Equal(t, 6, listener.lastGrow[1])
+	assert.Equal(t, stats.ObjectsCreated, listener.allocated)
+	assert.Positive(t, listener.l1Resizes)
+	assert.Positive(t, listener.growthBlocked)
+	assert.Equal(t, listener.waitStarts, listener.waitEnds)
+	listener.mu.Unlock()
+
+	for _, obj := range objects {
+		require.NoError(t, p.Put(obj))
+	}
+}
+
+func TestListenerShrinkEvents(t *testing.T) {
+	listener := &recordingListener{}
+	config, err := pool.NewPoolConfigBuilder[*TestObject]().
+		SetInitialCapacity(32).
+		EnforceCustomConfig().
+		SetShrinkCheckInterval(10*time.Millisecond).
+		SetShrinkCooldown(10*time.Millisecond).
+		SetMinUtilizationBeforeShrink(90).
+		SetStableUnderutilizationRounds(1).
+		SetShrinkPercent(50).
+		SetMinShrinkCapacity(1).
+		SetMaxConsecutiveShrinks(5).
+		SetFastPathBasicConfigs(32, 1, 1, 100, 20).
+		SetListener(listener).
+		Build()
+	require.NoError(t, err)
+
+	p := createTestPool(t, config)
+
+	obj, err := p.Get()
+	require.NoError(t, err)
+
+	require.Eventually(t, func() bool {
+		listener.mu.Lock()
+		defer listener.mu.Unlock()
+		return listener.shrinks > 0
+	}, time.Second, 10*time.Millisecond)
+
+	require.NoError(t, p.Put(obj))
+
+	require.NoError(t, p.Close())
+	stats := p.GetPoolStatsSnapshot()
+
+	listener.mu.Lock()
+	defer listener.mu.Unlock()
+	assert.Equal(t, stats.TotalShrinkEvents, listener.shrinks)
+	assert.Equal(t, stats.ObjectsDestroyed, listener.destroyed)
+}
+
+// the Get caller waits in the wait queue in fair mode, and blocks in the ring buffer otherwise
+func TestListenerWaitEvents(t *testing.T) {
+	t.Run("wait queue", func(t *testing.T) { testListenerWaitEvents(t, true) })
+	t.Run("ring buffer", func(t *testing.T) { testListenerWaitEvents(t, false) })
+}
+
+func testListenerWaitEvents(t *testing.T, fair bool) {
+	listener := &recordingListener{}
+	p, objects := createExhaustedPool(t, 2, func(b pool.PoolConfigBuilder[*TestObject]) pool.PoolConfigBuilder[*TestObject] {
+		return b.SetFairWaiting(fair).SetListener(listener)
+	})
+	defer func() {
+		require.NoError(t, p.Close())
+	}()
+
+	done := make(chan *TestObject)
+	go func() {
+		obj, err := p.Get()
+		assert.NoError(t, err)
+		done <- obj
+	}()
+
+	require.Eventually(t, func() bool {
+		listener.mu.Lock()
+		defer listener.mu.Unlock()
+		return listener.waitStarts == 1
+	}, time.Second, time.Millisecond)
+
+	listener.mu.Lock()
+	assert.Zero(t, listener.waitEnds, "the caller is still waiting")
+	listener.mu.Unlock()
+
+	require.NoError(t, p.Put(objects[0]))
+	objects[0] = <-done
+
+	listener.mu.Lock()
+	assert.Equal(t, 1, listener.waitStarts)
+	assert.Equal(t, 1, listener.waitEnds)
+	listener.mu.Unlock()
+
+	for _, obj := range objects {
+		require.NoError(t, p.Put(obj))
+	}
+}
@@ -0,0 +1,126 @@
+package test
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"sync"
+	"testing"
+
+	"github.com/AlexsanderHamir/PoolX/v2/pool"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// logRecorder collects the JSON records written by a slog.JSONHandler.
+type logRecorder struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (r *logRecorder) Write(p []byte) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.buf.Write(p)
+}
+
+func (r *logRecorder) records(t *testing.T) []map[string]any {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var records []map[string]any
+	for _, line := range bytes.Split(bytes.TrimSpace(r.buf.Bytes()), []byte("\n")) {
+		if len(line) == 0 {
+			continue
+		}
+		var record map[string]any
+		require.NoError(t, json.Unmarshal(line, &record))
+		records = append(records, record)
+	}
+	return records
+}
+
+func (r *logRecorder) find(t *testing.T, msg string) map[string]any {
+	for _, record := range r.records(t) {
+		if record["msg"] == msg {
+			return record
+		}
+	}
+	return nil
+}
+
+func TestLoggerReportsResizeDecisions(t *testing.T) {
+	recorder := &logRecorder{}
+	logger := slog.New(slog.NewJSONHandler(recorder, &slog.HandlerOptions{Level: slog.LevelDebug}))
+
+	config, err := pool.NewPoolConfigBuilder[*TestObject]().
+		SetInitialCapacity(2).
+		SetHardLimit(6).
+		SetMinShrinkCapacity(2).
+		SetGrowthExponentialThresholdFactor(4).
+		SetAllocationStrategy(100, 2).
+		SetFastPathGrowthEventsTrigger(1).
+		SetName("buffers").
+		SetLogger(logger).
+		Build()
+	require.NoError(t, err)
+
+	p := createTestPool(t, config)
+
+	objects := make([]*TestObject, 6)
+	for i := range objects {
+		objects[i], err = p.Get()
+		require.NoError(t, err)
+	}
+
+	_, err = p.Get()
+	require.Error(t, err)
+
+	grew := recorder.find(t, "pool grew")
+	require.NotNil(t, grew)
+	assert.Equal(t, "INFO", grew["level"])
+	assert.Equal(t, "buffers", grew["pool"])
+	assert.Contains(t, grew, "old_capacity")
+	assert.Contains(t, grew, "new_capacity")
+
+	assert.NotNil(t, recorder.find(t, "L1 cache grew"))
+	blocked := recorder.find(t, "growth blocked by the hard limit")
+	require.NotNil(t, blocked)
+	assert.Equal(t, "DEBUG", blocked["level"])
+
+	for _, obj := range objects {
+		require.NoError(t, p.Put(obj))
+	}
+	require.NoError(t, p.Close())
+}
+
+func TestLoggerReportsCallbackPanics(t *testing.T) {
+	recorder := &logRecorder{}
+
+	config, err := pool.NewPoolConfigBuilder[*TestObject]().
+		SetName("buffers").
+		SetLogger(slog.New(slog.NewJSONHandler(recorder, nil))).
+		Build()
+	require.NoError(t, err)
+
+	p, err := pool.NewPool(config,
+		func() *TestObject { return &TestObject{} },
+		func(obj *TestObject) { panic("cleaner exploded") },
+		func(obj *TestObject) *TestObject { dst := *obj; return &dst },
+	)
+	require.NoError(t, err)
+
+	obj, err := p.Get()
+	require.NoError(t, err)
+	require.Error(t, p.Put(obj))
+
+	record := recorder.find(t, "recovered panic in callback")
+	require.NotNil(t, record)
+	assert.Equal(t, "ERROR", record["level"])
+	assert.Equal(t, "buffers", record["pool"])
+	assert.Equal(t, pool.CallbackCleaner, record["callback"])
+	assert.Equal(t, "cleaner exploded", record["panic"])
+
+	require.NoError(t, p.Close())
+}
@@ -0,0 +1,90 @@
+package test
+
+import (
+	"bytes"
+	"context"
+	"runtime/pprof"
+	"testing"
+
+	"github.com/AlexsanderHamir/PoolX/v2/pool"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func createProfiledPool(t *testing.T, name string) *pool.Pool[*TestObject] {
+	config, err := pool.NewPoolConfigBuilder[*TestObject]().
+		SetName(name).
+		SetOutstandingProfile(true).
+		Build()
+	require.NoError(t, err)
+	assert.True(t, config.IsOutstandingProfileEnabled())
+
+	return createTestPool(t, config)
+}
+
+//go:noinline
+func leakObjects(t *testing.T, p *pool.Pool[*TestObject], n int) []*TestObject {
+	objs := make([]*TestObject, n)
+	for i := range objs {
+		obj, err := p.Get()
+		require.NoError(t, err)
+		objs[i] = obj
+	}
+	return objs
+}
+
+func TestOutstandingProfileRequiresName(t *testing.T) {
+	_, err := pool.NewPoolConfigBuilder[*TestObject]().
+		SetOutstandingProfile(true).
+		Build()
+	assert.Error(t, err)
+}
+
+func TestOutstandingProfileDisabledByDefault(t *testing.T) {
+	p := createTestPool(t, nil)
+	defer p.Close()
+
+	assert.Nil(t, p.OutstandingProfile())
+}
+
+func TestOutstandingProfile(t *testing.T) {
+	p := createProfiledPool(t, "outstanding")
+	defer p.Close()
+
+	profile := p.OutstandingProfile()
+	require.NotNil(t, profile)
+	assert.Equal(t, "poolx.outstanding.outstanding", profile.Name())
+	assert.Same(t, profile, pprof.Lookup("poolx.outstanding.outstanding"))
+
+	objs := leakObjects(t, p, 3)
+	assert.Equal(t, 3, profile.Count())
+
+	var buf bytes.Buffer
+	require.NoError(t, profile.WriteTo(&buf, 1))
+	assert.Contains(t, buf.String(), "test.leakObjects", "the stack starts at the caller of Get")
+	assert.NotContains(t, buf.String(), "trackOutstanding")
+
+	batch, err := p.GetAtomic(context.Background(), 2)
+	require.NoError(t, err)
+	assert.Equal(t, 5, profile.Count())
+
+	obj, err := p.GetPriority(context.Background(), 1)
+	require.NoError(t, err)
+	assert.Equal(t, 6, profile.Count())
+
+	for _, obj := range append(append(objs, batch...), obj) {
+		require.NoError(t, p.Put(obj))
+	}
+	assert.Zero(t, profile.Count())
+}
+
+func TestOutstandingProfileIsReused(t *testing.T) {
+	first := createProfiledPool(t, "reused")
+	defer first.Close()
+
+	second := createProfiledPool(t, "reused")
+	defer second.Close()
+
+	assert.Same(t, first.OutstandingProfile(), second.OutstandingProfile())
+}
@@ -0,0 +1,105 @@
+package test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/AlexsanderHamir/PoolX/v2/pool"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReconfigure(t *testing.T) {
+	config, err := pool.NewPoolConfigBuilder[*TestObject]().
+		SetName("orders").
+		SetPoolBasicConfigs(64, 1024, true).
+		Build()
+	require.NoError(t, err)
+
+	p := createTestPool(t, config)
+	defer p.Close()
+
+	spec := p.GetConfigSpec()
+	spec.HardLimit = 4096
+	spec.Growth.ControlledGrowthFactor = 0.25
+	spec.Shrink.CheckInterval = 20 * time.Millisecond
+	spec.Shrink.ShrinkPercent = 10
+	require.NoError(t, p.Reconfigure(spec))
+
+	assert.Equal(t, spec, p.GetConfigSpec())
+	assert.Equal(t, 1024, config.GetHardLimit(), "the configuration the pool was created with is left untouched")
+}
+
+func TestReconfigureRejected(t *testing.T) {
+	p := createNamedPool(t, "orders")
+	defer p.Close()
+
+	before := p.GetConfigSpec()
+
+	invalid := before
+	invalid.HardLimit = 16
+	err := p.Reconfigure(invalid)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `pool "orders": invalid configuration`)
+	assert.Contains(t, err.Error(), "hardLimit (16) must be >= initialCapacity (64)")
+
+	fixed := before
+	fixed.InitialCapacity = 128
+	fixed.FastPath.InitialSize = 128
+	err = p.Reconfigure(fixed)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "fast_path.initial_size, initial_capacity can't be changed on a running pool")
+
+	assert.Equal(t, before, p.GetConfigSpec())
+}
+
+func TestReconfigureHardLimitUnblocksGrowth(t *testing.T) {
+	config, err := pool.NewPoolConfigBuilder[*TestObject]().
+		SetPoolBasicConfigs(8, 8, true).
+		SetMinShrinkCapacity(8).
+		SetGrowthExponentialThresholdFactor(4).
+		SetAllocationStrategy(100, 8).
+		SetFastPathBasicConfigs(8, 3, 3, 100, 20).
+		SetFastPathShrinkMinCapacity(8).
+		Build()
+	require.NoError(t, err)
+
+	p := createTestPool(t, config)
+	defer p.Close()
+
+	objs := exhaust(t, p, 8)
+	_, err = p.Get()
+	require.Error(t, err, "the pool is at its hard limit")
+
+	spec := p.GetConfigSpec()
+	spec.HardLimit = 64
+	require.NoError(t, p.Reconfigure(spec))
+
+	obj, err := p.Get()
+	require.NoError(t, err)
+	assert.Greater(t, p.RingBufferCapacity(), 8)
+
+	for _, o := range append(objs, obj) {
+		require.NoError(t, p.Put(o))
+	}
+}
+
+func TestReconfigureClosedPool(t *testing.T) {
+	p := createNamedPool(t, "orders")
+	spec := p.GetConfigSpec()
+	require.NoError(t, p.Close())
+
+	spec.HardLimit = 4096
+	assert.Error(t, p.Reconfigure(spec))
+}
+
+func TestParseConfigSpecEnforcedCustomConfig(t *testing.T) {
+	_, err := pool.ParseConfigSpec([]byte(`{"shrink": {"enforce_custom_config": true, "aggressiveness_level": "extreme"}}`))
+	assert.Error(t, err)
+
+	spec, err := pool.ParseConfigSpec([]byte(`{"shrink": {"enforce_custom_config": true, "aggressiveness_level": "disabled"}}`))
+	require.NoError(t, err)
+	assert.True(t, spec.Shrink.EnforceCustomConfig)
+	assert.Zero(t, spec.Shrink.CheckInterval)
+}
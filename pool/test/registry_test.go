@@ -0,0 +1,164 @@
+package test
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/AlexsanderHamir/PoolX/v2/pool"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// closeRecorder is a registered pool that records when it's closed.
+type closeRecorder struct {
+	*pool.Pool[*TestObject]
+	closed  *[]string
+	mu      *sync.Mutex
+	release chan struct{}
+}
+
+func (c *closeRecorder) Close() error {
+	if c.release != nil {
+		<-c.release
+	}
+
+	c.mu.Lock()
+	*c.closed = append(*c.closed, c.Name())
+	c.mu.Unlock()
+
+	return c.Pool.Close()
+}
+
+func createNamedPool(t *testing.T, name string) *pool.Pool[*TestObject] {
+	config, err := pool.NewPoolConfigBuilder[*TestObject]().
+		SetName(name).
+		Build()
+	require.NoError(t, err)
+
+	return createTestPool(t, config)
+}
+
+func TestRegistryRegister(t *testing.T) {
+	r := pool.NewRegistry()
+	orders := createNamedPool(t, "orders")
+	defer orders.Close()
+
+	require.NoError(t, r.Register(orders, map[string]string{"message_type": "order"}))
+
+	assert.Error(t, r.Register(nil, nil))
+	assert.Error(t, r.Register(orders, nil), "duplicate name")
+	assert.Error(t, r.Register(createTestPool(t, nil), nil), "pool without a name")
+
+	invoices := createNamedPool(t, "invoices")
+	defer invoices.Close()
+	assert.Error(t, r.Register(invoices, map[string]string{"pool": "x"}))
+	assert.Error(t, r.Register(invoices, map[string]string{"message-type": "x"}))
+
+	entry, ok := r.Lookup("orders")
+	require.True(t, ok)
+	assert.Equal(t, "orders", entry.Name)
+	assert.Equal(t, map[string]string{"message_type": "order"}, entry.Labels)
+
+	typed, ok := pool.LookupPool[*TestObject](r, "orders")
+	require.True(t, ok)
+	assert.Same(t, orders, typed)
+
+	_, ok = pool.LookupPool[*TestBuffer](r, "orders")
+	assert.False(t, ok, "wrong object type")
+
+	_, ok = r.Lookup("invoices")
+	assert.False(t, ok)
+
+	assert.True(t, r.Unregister("orders"))
+	assert.False(t, r.Unregister("orders"))
+	assert.Empty(t, r.Pools())
+}
+
+func TestRegistryCloseAllOrder(t *testing.T) {
+	r := pool.NewRegistry()
+
+	var (
+		mu     sync.Mutex
+		closed []string
+	)
+	names := []string{"first", "second", "third"}
+	for _, name := range names {
+		p := &closeRecorder{Pool: createNamedPool(t, name), closed: &closed, mu: &mu}
+		require.NoError(t, r.Register(p, nil))
+	}
+
+	var registered []string
+	for _, entry := range r.Pools() {
+		registered = append(registered, entry.Name)
+	}
+	assert.Equal(t, names, registered, "pools are enumerated in registration order")
+
+	require.NoError(t, r.CloseAll(context.Background()))
+	assert.Equal(t, []string{"third", "second", "first"}, closed)
+	assert.Empty(t, r.Pools())
+}
+
+func TestRegistryCloseAllGivesUpOnContext(t *testing.T) {
+	r := pool.NewRegistry()
+
+	var (
+		mu     sync.Mutex
+		closed []string
+	)
+	release := make(chan struct{})
+	require.NoError(t, r.Register(&closeRecorder{Pool: createNamedPool(t, "first"), closed: &closed, mu: &mu}, nil))
+	require.NoError(t, r.Register(&closeRecorder{Pool: createNamedPool(t, "stuck"), closed: &closed, mu: &mu, release: release}, nil))
+	defer close(release)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	err := r.CloseAll(ctx)
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Contains(t, err.Error(), `"stuck" "first"`)
+	assert.Len(t, r.Pools(), 2, "pools that weren't closed stay registered")
+
+	mu.Lock()
+	assert.Empty(t, closed)
+	mu.Unlock()
+}
+
+func TestRegistryCloseOnSignal(t *testing.T) {
+	r := pool.NewRegistry()
+	require.NoError(t, r.Register(createNamedPool(t, "orders"), nil))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() {
+		done <- r.CloseOnSignal(ctx, time.Second)
+	}()
+
+	cancel()
+
+	select {
+	case err := <-done:
+		require.NoError(t, err)
+	case <-time.After(5 * time.Second):
+		t.Fatal("CloseOnSignal did not return")
+	}
+	assert.Empty(t, r.Pools())
+}
+
+func TestPoolNameInErrors(t *testing.T) {
+	p := createNamedPool(t, "orders")
+	defer p.Close()
+
+	_, err := p.GetAtomic(context.Background(), 0)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `pool "orders": `)
+
+	unnamed := createTestPool(t, nil)
+	defer unnamed.Close()
+
+	_, err = unnamed.GetAtomic(context.Background(), 0)
+	require.Error(t, err)
+	assert.NotContains(t, err.Error(), "pool \"")
+}
@@ -0,0 +1,154 @@
+package test
+
+import (
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/AlexsanderHamir/PoolX/v2/pool"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConsistentSnapshotUnderLoad(t *testing.T) {
+	p := createTestPool(t, nil)
+	defer p.Close()
+
+	const workers = 8
+
+	var (
+		stop atomic.Bool
+		wg   sync.WaitGroup
+	)
+	for range workers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for !stop.Load() {
+				obj, err := p.Get()
+				if err != nil {
+					continue
+				}
+				_ = p.Put(obj)
+			}
+		}()
+	}
+
+	for range 200 {
+		s := p.GetStatsSnapshot(pool.SnapshotConsistent)
+		require.LessOrEqual(t, s.ObjectsInUse, uint64(workers), "a worker holds at most one object")
+		require.LessOrEqual(t, s.Utilization, 1.0)
+	}
+
+	stop.Store(true)
+	wg.Wait()
+
+	s := p.GetStatsSnapshot(pool.SnapshotConsistent)
+	assert.Zero(t, s.ObjectsInUse)
+	assert.Equal(t, s.TotalGets, s.TotalReturns())
+}
+
+func TestConsistentSnapshotDoesNotFailGets(t *testing.T) {
+	const held = 8
+
+	config, err := pool.NewPoolConfigBuilder[*TestObject]().
+		SetPoolBasicConfigs(8, 1024, false).
+		SetMinShrinkCapacity(8).
+		Build()
+	require.NoError(t, err)
+
+	p := createTestPool(t, config)
+	defer p.Close()
+
+	// a single caller never competes for a refill and the pool stays well below its hard limit,
+	// so every Get must succeed while the snapshots are taken
+	var (
+		stop     atomic.Bool
+		failures int
+		done     = make(chan struct{})
+	)
+	go func() {
+		defer close(done)
+		objs := make([]*TestObject, 0, held)
+		for !stop.Load() {
+			for range held {
+				obj, err := p.Get()
+				if err != nil {
+					failures++
+					continue
+				}
+				objs = append(objs, obj)
+			}
+			for _, obj := range objs {
+				_ = p.Put(obj)
+			}
+			objs = objs[:0]
+		}
+	}()
+
+	for deadline := time.Now().Add(200 * time.Millisecond); time.Now().Before(deadline); {
+		p.GetStatsSnapshot(pool.SnapshotConsistent)
+	}
+
+	stop.Store(true)
+	<-done
+
+	assert.Zero(t, failures)
+}
+
+func TestSnapshotDoesNotUnderflow(t *testing.T) {
+	p := createTestPool(t, nil)
+	defer p.Close()
+
+	obj, err := p.Get()
+	require.NoError(t, err)
+	require.NoError(t, p.Put(obj))
+	require.NoError(t, p.Put(obj), "returning an object twice is a caller bug the stats must survive")
+
+	for _, mode := range []pool.SnapshotMode{pool.SnapshotFast, pool.SnapshotConsistent} {
+		s := p.GetStatsSnapshot(mode)
+		assert.Zero(t, s.ObjectsInUse, mode.String())
+		assert.Zero(t, s.Utilization, mode.String())
+		assert.Equal(t, s.CurrentCapacity, s.AvailableObjects, mode.String())
+	}
+}
+
+func TestSnapshotDelta(t *testing.T) {
+	p := createTestPool(t, nil)
+	defer p.Close()
+
+	prev := p.GetStatsSnapshot(pool.SnapshotConsistent)
+	time.Sleep(10 * time.Millisecond)
+
+	for range 5 {
+		obj, err := p.Get()
+		require.NoError(t, err)
+		require.NoError(t, p.Put(obj))
+	}
+
+	cur := p.GetStatsSnapshot(pool.SnapshotConsistent)
+
+	delta := cur.Delta(prev)
+	assert.Equal(t, uint64(5), delta.Gets)
+	assert.Equal(t, uint64(5), delta.Returns)
+	assert.Equal(t, uint64(5), delta.L1Hits)
+	assert.GreaterOrEqual(t, delta.Interval, 10*time.Millisecond)
+	assert.Positive(t, delta.PerSecond(delta.Gets))
+
+	backwards := prev.Delta(cur)
+	assert.Zero(t, backwards.Gets, "counters going backwards must not wrap around")
+	assert.Zero(t, backwards.Returns)
+	assert.Zero(t, backwards.PerSecond(backwards.Gets))
+
+	total := cur.Delta(nil)
+	assert.Equal(t, cur.TotalGets, total.Gets)
+	assert.Equal(t, uint64(cur.ObjectsCreated), total.ObjectsCreated)
+}
+
+func TestSnapshotModeString(t *testing.T) {
+	assert.Equal(t, "fast", pool.SnapshotFast.String())
+	assert.Equal(t, "consistent", pool.SnapshotConsistent.String())
+	assert.Equal(t, "SnapshotMode(7)", pool.SnapshotMode(7).String())
+}
@@ -0,0 +1,89 @@
+package test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/AlexsanderHamir/PoolX/v2/pool"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStatsHistoryIsBounded(t *testing.T) {
+	config, err := pool.NewPoolConfigBuilder[*TestObject]().
+		SetStatsHistory(5*time.Millisecond, 4).
+		Build()
+	require.NoError(t, err)
+
+	p := createTestPool(t, config)
+	defer func() {
+		require.NoError(t, p.Close())
+	}()
+
+	require.Eventually(t, func() bool {
+		return len(p.StatsHistory()) == 4
+	}, time.Second, 5*time.Millisecond)
+
+	time.Sleep(30 * time.Millisecond)
+
+	history := p.StatsHistory()
+	require.Len(t, history, 4)
+	for i := 1; i < len(history); i++ {
+		assert.True(t, history[i].Timestamp.After(history[i-1].Timestamp), "samples are ordered oldest first")
+	}
+}
+
+func TestStatsWindow(t *testing.T) {
+	config, err := pool.NewPoolConfigBuilder[*TestObject]().
+		SetStatsHistory(5*time.Millisecond, 100).
+		Build()
+	require.NoError(t, err)
+
+	p := createTestPool(t, config)
+
+	var objects []*TestObject
+	for range 10 {
+		obj, err := p.Get()
+		require.NoError(t, err)
+		objects = append(objects, obj)
+		time.Sleep(5 * time.Millisecond)
+	}
+
+	require.Eventually(t, func() bool {
+		history := p.StatsHistory()
+		return history[len(history)-1].ObjectsInUse == 10
+	}, time.Second, 5*time.Millisecond)
+
+	window, ok := p.StatsWindow(0)
+	require.True(t, ok)
+	assert.Greater(t, window.Samples, 1)
+	assert.Equal(t, uint64(0), window.InUseMin)
+	assert.Equal(t, uint64(10), window.InUseMax)
+	assert.LessOrEqual(t, window.InUseP50, window.InUseP90)
+	assert.LessOrEqual(t, window.InUseP90, window.InUseP99)
+	assert.Positive(t, window.GetsPerSecond)
+	assert.True(t, window.End.After(window.Start))
+
+	recent, ok := p.StatsWindow(time.Nanosecond)
+	require.True(t, ok)
+	assert.Equal(t, 1, recent.Samples)
+	assert.Zero(t, recent.GetsPerSecond)
+	assert.Equal(t, uint64(10), recent.InUseMin)
+
+	for _, obj := range objects {
+		require.NoError(t, p.Put(obj))
+	}
+	require.NoError(t, p.Close())
+}
+
+func TestStatsHistoryDisabledByDefault(t *testing.T) {
+	p := createTestPool(t, nil)
+	defer func() {
+		require.NoError(t, p.Close())
+	}()
+
+	assert.Nil(t, p.StatsHistory())
+	_, ok := p.StatsWindow(time.Minute)
+	assert.False(t, ok)
+}
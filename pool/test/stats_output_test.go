@@ -0,0 +1,139 @@
+package test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/AlexsanderHamir/PoolX/v2/pool"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newStatsOutputPool(t *testing.T) *pool.Pool[*TestObject] {
+	config, err := pool.NewPoolConfigBuilder[*TestObject]().
+		SetMaxWaiters(4).
+		SetGetLatencyHistograms(true).
+		Build()
+	require.NoError(t, err)
+
+	p := createTestPool(t, config)
+	t.Cleanup(func() {
+		require.NoError(t, p.Close())
+	})
+
+	obj, err := p.Get()
+	require.NoError(t, err)
+	require.NoError(t, p.Put(obj))
+
+	return p
+}
+
+func TestWriteStatsText(t *testing.T) {
+	p := newStatsOutputPool(t)
+
+	var buf bytes.Buffer
+	require.NoError(t, p.WriteStats(&buf, pool.FormatText))
+
+	out := buf.String()
+	assert.True(t, strings.HasPrefix(out, "=== Pool Statistics ===\n"))
+	assert.Contains(t, out, "Total gets: 1\n")
+	assert.Contains(t, out, "Waiting callers: 0\n", "admission control is configured")
+	assert.NotContains(t, out, "Circuit state", "the circuit breaker is not configured")
+	assert.Contains(t, out, "Get latency (l1_hit): count 1")
+}
+
+func TestWriteStatsTable(t *testing.T) {
+	p := newStatsOutputPool(t)
+
+	var buf bytes.Buffer
+	require.NoError(t, p.WriteStats(&buf, pool.FormatTable))
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	require.Greater(t, len(lines), 1)
+	assert.True(t, strings.HasPrefix(lines[0], "STATISTIC"))
+
+	valueColumn := strings.Index(lines[0], "VALUE")
+	for _, line := range lines[1:] {
+		assert.NotEqual(t, ' ', rune(line[valueColumn]), "values are aligned: %q", line)
+		assert.Equal(t, ' ', rune(line[valueColumn-1]), "values are aligned: %q", line)
+	}
+}
+
+func TestWriteStatsJSON(t *testing.T) {
+	p := newStatsOutputPool(t)
+
+	var buf bytes.Buffer
+	require.NoError(t, p.WriteStats(&buf, pool.FormatJSON))
+
+	var decoded map[string]any
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &decoded))
+	assert.Equal(t, float64(1), decoded["total_gets"])
+	assert.Contains(t, decoded, "objects_in_use")
+	assert.Contains(t, decoded, "get_latency")
+}
+
+func TestWriteStatsUnknownFormat(t *testing.T) {
+	p := newStatsOutputPool(t)
+	assert.Error(t, p.WriteStats(&bytes.Buffer{}, pool.Format(42)))
+}
+
+func TestSnapshotMarshalers(t *testing.T) {
+	p := newStatsOutputPool(t)
+	stats := p.GetPoolStatsSnapshot()
+
+	encoded, err := json.Marshal(stats)
+	require.NoError(t, err)
+	assert.Contains(t, string(encoded), `"total_gets":1`)
+	assert.Contains(t, string(encoded), `"l2_spill_rate":`)
+
+	var decoded pool.PoolStatsSnapshot
+	require.NoError(t, json.Unmarshal(encoded, &decoded))
+	assert.Equal(t, stats.TotalGets, decoded.TotalGets)
+
+	text, err := stats.MarshalText()
+	require.NoError(t, err)
+	assert.Contains(t, string(text), "Total gets: 1\n")
+	assert.Contains(t, string(text), "Circuit state: \n", "every group is rendered without the pool configuration")
+
+	extended, err := json.Marshal(p.GetExtendedStatsSnapshot())
+	require.NoError(t, err)
+	assert.Contains(t, string(extended), `"total_gets":1`)
+	assert.Contains(t, string(extended), `"get_latency":{`)
+}
+
+func TestWriteStatsWaitQueueAndUtilization(t *testing.T) {
+	p, objects := createExhaustedPool(t, 2, func(b pool.PoolConfigBuilder[*TestObject]) pool.PoolConfigBuilder[*TestObject] {
+		return b
+	})
+	defer func() {
+		require.NoError(t, p.Close())
+	}()
+
+	var buf bytes.Buffer
+	require.NoError(t, p.WriteStats(&buf, pool.FormatText))
+	assert.Contains(t, buf.String(), "Utilization: 100.00%\n")
+	assert.NotContains(t, buf.String(), "Total waits", "nobody went through the wait queue yet")
+
+	done := make(chan *TestObject)
+	go func() {
+		obj, err := p.GetPriority(context.Background(), 0)
+		assert.NoError(t, err)
+		done <- obj
+	}()
+	waitForWaiters(t, p, 1)
+
+	require.NoError(t, p.Put(objects[0]))
+	objects[0] = <-done
+
+	buf.Reset()
+	require.NoError(t, p.WriteStats(&buf, pool.FormatText))
+	assert.Contains(t, buf.String(), "Total waits: 1\n")
+
+	for _, obj := range objects {
+		require.NoError(t, p.Put(obj))
+	}
+}
@@ -0,0 +1,213 @@
+package test
+
+import (
+	"bytes"
+	"context"
+	"runtime/pprof"
+	"runtime/trace"
+	"slices"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/AlexsanderHamir/PoolX/v2/pool"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// exhaust takes n objects from the pool, forcing it through its refill and growth paths.
+func exhaust(t *testing.T, p *pool.Pool[*TestObject], n int) []*TestObject {
+	objs := make([]*TestObject, n)
+	for i := range objs {
+		obj, err := p.Get()
+		require.NoError(t, err)
+		objs[i] = obj
+	}
+	return objs
+}
+
+func TestTraceRegions(t *testing.T) {
+	config, err := pool.NewPoolConfigBuilder[*TestObject]().
+		SetPoolBasicConfigs(8, 256, true).
+		SetMinShrinkCapacity(8).
+		SetGrowthExponentialThresholdFactor(4).
+		SetAllocationStrategy(100, 8).
+		Build()
+	require.NoError(t, err)
+
+	p := createTestPool(t, config)
+	defer p.Close()
+
+	var buf bytes.Buffer
+	require.NoError(t, trace.Start(&buf))
+	objs := exhaust(t, p, 64)
+	trace.Stop()
+
+	require.Positive(t, p.GetPoolStatsSnapshot().TotalGrowthEvents)
+	for _, region := range []string{"poolx.refill", "poolx.grow", "poolx.update_capacity"} {
+		assert.Contains(t, buf.String(), region)
+	}
+
+	for _, obj := range objs {
+		require.NoError(t, p.Put(obj))
+	}
+}
+
+func TestProfilerLabels(t *testing.T) {
+	config, err := pool.NewPoolConfigBuilder[*TestObject]().
+		SetName("orders").
+		SetFastPathBackgroundReplenisher(true, time.Hour).
+		SetProfilerLabels(true).
+		Build()
+	require.NoError(t, err)
+	assert.True(t, config.IsProfilerLabelsEnabled())
+
+	p := createTestPool(t, config)
+	defer p.Close()
+
+	// the background goroutines label themselves when they start
+	for _, role := range []string{"shrink", "replenish"} {
+		assert.Eventually(t, func() bool {
+			return slices.ContainsFunc(goroutineLabels(t), func(labels string) bool {
+				return strings.Contains(labels, `"poolx.op":"`+role+`"`) && strings.Contains(labels, `"poolx.pool":"orders"`)
+			})
+		}, time.Second, time.Millisecond, role)
+	}
+}
+
+// newBlockingAllocatorPool creates a labeled pool whose allocator blocks, once armed, until release is closed.
+func newBlockingAllocatorPool(t *testing.T, block *atomic.Bool, entered, release chan struct{}) *pool.Pool[*TestObject] {
+	allocator := func() *TestObject {
+		if block.CompareAndSwap(true, false) {
+			close(entered)
+			<-release
+		}
+		return &TestObject{}
+	}
+
+	config, err := pool.NewPoolConfigBuilder[*TestObject]().
+		SetPoolBasicConfigs(8, 256, true).
+		SetMinShrinkCapacity(8).
+		SetGrowthExponentialThresholdFactor(4).
+		SetAllocationStrategy(100, 8).
+		SetName("orders").
+		SetProfilerLabels(true).
+		Build()
+	require.NoError(t, err)
+
+	obj, err := pool.NewPool(config, allocator, func(obj *TestObject) {}, nil)
+	require.NoError(t, err)
+	return obj.(*pool.Pool[*TestObject])
+}
+
+func TestProfilerLabelsLeaveCallerLabels(t *testing.T) {
+	var (
+		block   atomic.Bool
+		entered = make(chan struct{})
+		release = make(chan struct{})
+	)
+	p := newBlockingAllocatorPool(t, &block, entered, release)
+	defer p.Close()
+
+	objs := exhaust(t, p, 8)
+
+	// the caller grows the pool through Get, its labels are checked during and after the allocation
+	block.Store(true)
+	var (
+		returned = make(chan struct{})
+		checked  = make(chan struct{})
+		done     = make(chan []*TestObject)
+	)
+	go pprof.Do(context.Background(), pprof.Labels("app", "checkout"), func(ctx context.Context) {
+		objs := exhaust(t, p, 8)
+		close(returned)
+		<-checked
+		done <- objs
+	})
+	<-entered
+
+	during := goroutineLabels(t)
+	close(release)
+	<-returned
+	after := goroutineLabels(t)
+	close(checked)
+	objs = append(objs, <-done...)
+
+	for name, labels := range map[string][]string{"during": during, "after": after} {
+		caller := slices.IndexFunc(labels, func(labels string) bool {
+			return strings.Contains(labels, `"app":"checkout"`)
+		})
+		require.NotEqual(t, -1, caller, "%s the allocation the caller keeps its labels: %v", name, labels)
+		assert.NotContains(t, labels[caller], "poolx.", "%s the allocation", name)
+	}
+
+	for _, obj := range objs {
+		require.NoError(t, p.Put(obj))
+	}
+}
+
+func TestProfilerLabelsRestoreContextLabels(t *testing.T) {
+	var (
+		block   atomic.Bool
+		entered = make(chan struct{})
+		release = make(chan struct{})
+	)
+	p := newBlockingAllocatorPool(t, &block, entered, release)
+	defer p.Close()
+
+	objs := exhaust(t, p, 8)
+
+	// the caller grows the pool through GetPriority, its labels are checked during and after the call
+	block.Store(true)
+	var (
+		returned = make(chan struct{})
+		checked  = make(chan struct{})
+		done     = make(chan *TestObject)
+	)
+	go pprof.Do(context.Background(), pprof.Labels("app", "checkout"), func(ctx context.Context) {
+		obj, err := p.GetPriority(ctx, 0)
+		assert.NoError(t, err)
+		close(returned)
+		<-checked
+		done <- obj
+	})
+	<-entered
+
+	during := goroutineLabels(t)
+	close(release)
+	<-returned
+	after := goroutineLabels(t)
+	close(checked)
+	objs = append(objs, <-done)
+
+	assert.True(t, slices.ContainsFunc(during, func(labels string) bool {
+		return strings.Contains(labels, `"app":"checkout"`) &&
+			strings.Contains(labels, `"poolx.pool":"orders"`) && strings.Contains(labels, `"poolx.op":"get_priority"`)
+	}), "the caller is labeled on top of its own labels: %v", during)
+
+	caller := slices.IndexFunc(after, func(labels string) bool {
+		return strings.Contains(labels, `"app":"checkout"`)
+	})
+	require.NotEqual(t, -1, caller, "the caller gets its labels back when GetPriority returns")
+	assert.NotContains(t, after[caller], "poolx.")
+
+	for _, obj := range objs {
+		require.NoError(t, p.Put(obj))
+	}
+}
+
+// goroutineLabels returns the label sets of the goroutines found in the goroutine profile.
+func goroutineLabels(t *testing.T) []string {
+	var profile bytes.Buffer
+	require.NoError(t, pprof.Lookup("goroutine").WriteTo(&profile, 1))
+
+	var labels []string
+	for _, line := range strings.Split(profile.String(), "\n") {
+		if rest, ok := strings.CutPrefix(line, "# labels: "); ok {
+			labels = append(labels, rest)
+		}
+	}
+	return labels
+}
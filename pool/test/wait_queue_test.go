@@ -0,0 +1,388 @@
+package test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/AlexsanderHamir/PoolX/v2/pool"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// createExhaustedPool creates a blocking pool limited to hardLimit objects and takes all of them.
+func createExhaustedPool(t *testing.T, hardLimit int, configure func(pool.PoolConfigBuilder[*TestObject]) pool.PoolConfigBuilder[*TestObject]) (*pool.Pool[*TestObject], []*TestObject) {
+	builder := pool.NewPoolConfigBuilder[*TestObject]().
+		SetInitialCapacity(hardLimit).
+		SetHardLimit(hardLimit).
+		SetMinShrinkCapacity(hardLimit).
+		SetRingBufferBlocking(true).
+		SetAllocationStrategy(100, hardLimit)
+
+	config, err := configure(builder).Build()
+	require.NoError(t, err)
+
+	p := createTestPool(t, config)
+
+	objects := make([]*TestObject, hardLimit)
+	for i := range objects {
+		objects[i], err = p.Get()
+		require.NoError(t, err)
+		require.NotNil(t, objects[i])
+	}
+
+	return p, objects
+}
+
+// waitForWaiters blocks until the pool has n queued waiters. The snapshot is taken in consistent mode,
+// which reads the pool state under its locks while the waiters are being queued.
+func waitForWaiters(t *testing.T, p *pool.Pool[*TestObject], n int) {
+	require.Eventually(t, func() bool {
+		return p.GetStatsSnapshot(pool.SnapshotConsistent).Waiters == n
+	}, time.Second, time.Millisecond)
+}
+
+func TestFairWaitingOrder(t *testing.T) {
+	p, objects := createExhaustedPool(t, 4, func(b pool.PoolConfigBuilder[*TestObject]) pool.PoolConfigBuilder[*TestObject] {
+		return b.SetFairWaiting(true)
+	})
+	defer func() {
+		require.NoError(t, p.Close())
+	}()
+
+	type servedWaiter struct {
+		id  int
+		obj *TestObject
+	}
+
+	// the waiters keep their objects until the order is checked, so each Put below serves exactly one of them
+	const numWaiters = 3
+	served := make(chan servedWaiter, numWaiters)
+	for i := range numWaiters {
+		go func(id int) {
+			obj, err := p.Get()
+			assert.NoError(t, err)
+			assert.NotNil(t, obj)
+			served <- servedWaiter{id: id, obj: obj}
+		}(i)
+		waitForWaiters(t, p, i+1)
+	}
+
+	var held []*TestObject
+	for i := range numWaiters {
+		require.NoError(t, p.Put(objects[i]))
+		select {
+		case w := <-served:
+			assert.Equal(t, i, w.id)
+			held = append(held, w.obj)
+		case <-time.After(time.Second):
+			t.Fatalf("waiter %d was not served after returning an object", i)
+		}
+	}
+
+	for _, obj := range append(held, objects[numWaiters:]...) {
+		require.NoError(t, p.Put(obj))
+	}
+
+	stats := p.GetStatsSnapshot(pool.SnapshotConsistent)
+	assert.Equal(t, 0, stats.Waiters)
+	assert.Equal(t, uint64(numWaiters), stats.TotalWaits)
+	assert.Greater(t, stats.MaxWaitTime, time.Duration(0))
+	assert.Equal(t, uint64(0), stats.ObjectsInUse)
+}
+
+func TestFairWaitingTimeout(t *testing.T) {
+	p, objects := createExhaustedPool(t, 2, func(b pool.PoolConfigBuilder[*TestObject]) pool.PoolConfigBuilder[*TestObject] {
+		return b.SetFairWaiting(true).SetRingBufferTimeout(50 * time.Millisecond)
+	})
+	defer func() {
+		require.NoError(t, p.Close())
+	}()
+
+	start := time.Now()
+	obj, err := p.Get()
+	assert.Error(t, err)
+	assert.Nil(t, obj)
+	assert.GreaterOrEqual(t, time.Since(start), 50*time.Millisecond)
+	assert.Equal(t, 0, p.GetPoolStatsSnapshot().Waiters)
+
+	for _, obj := range objects {
+		require.NoError(t, p.Put(obj))
+	}
+}
+
+func TestFairWaitingReadTimeout(t *testing.T) {
+	p, objects := createExhaustedPool(t, 2, func(b pool.PoolConfigBuilder[*TestObject]) pool.PoolConfigBuilder[*TestObject] {
+		return b.SetFairWaiting(true).SetRingBufferReadTimeout(50 * time.Millisecond)
+	})
+	defer func() {
+		require.NoError(t, p.Close())
+	}()
+
+	done := make(chan error, 1)
+	go func() {
+		_, err := p.Get()
+		done <- err
+	}()
+
+	select {
+	case err := <-done:
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+	case <-time.After(time.Second):
+		t.Fatal("the wait is not bounded by the read timeout")
+	}
+
+	for _, obj := range objects {
+		require.NoError(t, p.Put(obj))
+	}
+}
+
+func TestGetBlocksInRingBufferWithoutFairMode(t *testing.T) {
+	p, objects := createExhaustedPool(t, 2, func(b pool.PoolConfigBuilder[*TestObject]) pool.PoolConfigBuilder[*TestObject] {
+		return b
+	})
+	defer func() {
+		require.NoError(t, p.Close())
+	}()
+
+	done := make(chan *TestObject, 1)
+	go func() {
+		obj, err := p.Get()
+		assert.NoError(t, err)
+		done <- obj
+	}()
+
+	require.Eventually(t, func() bool {
+		return p.GetBlockedReaders() == 1
+	}, time.Second, time.Millisecond)
+	assert.Equal(t, 0, p.GetPoolStatsSnapshot().Waiters, "Get callers only join the wait queue in fair mode")
+
+	require.NoError(t, p.Put(objects[0]))
+	select {
+	case obj := <-done:
+		assert.NotNil(t, obj)
+		objects[0] = obj
+	case <-time.After(time.Second):
+		t.Fatal("the blocked reader was not woken up by the returned object")
+	}
+
+	stats := p.GetPoolStatsSnapshot()
+	assert.Zero(t, stats.TotalWaits)
+	assert.Zero(t, stats.WaitQueueHandoffs)
+
+	for _, obj := range objects {
+		require.NoError(t, p.Put(obj))
+	}
+}
+
+func TestGetPriorityOrder(t *testing.T) {
+	p, objects := createExhaustedPool(t, 4, func(b pool.PoolConfigBuilder[*TestObject]) pool.PoolConfigBuilder[*TestObject] {
+		return b
+	})
+	defer func() {
+		require.NoError(t, p.Close())
+	}()
+
+	priorities := []int{1, 10, 1, 5}
+	expectedOrder := []int{1, 3, 0, 2} // indexes into priorities
+
+	type servedWaiter struct {
+		id  int
+		obj *TestObject
+	}
+
+	served := make(chan servedWaiter, len(priorities))
+	for i, priority := range priorities {
+		go func(id, priority int) {
+			obj, err := p.GetPriority(context.Background(), priority)
+			assert.NoError(t, err)
+			assert.NotNil(t, obj)
+			served <- servedWaiter{id: id, obj: obj}
+		}(i, priority)
+		waitForWaiters(t, p, i+1)
+	}
+
+	var held []*TestObject
+	for i, expected := range expectedOrder {
+		require.NoError(t, p.Put(objects[i]))
+		select {
+		case w := <-served:
+			assert.Equal(t, expected, w.id)
+			held = append(held, w.obj)
+		case <-time.After(time.Second):
+			t.Fatalf("waiter %d was not served after returning an object", expected)
+		}
+	}
+
+	for _, obj := range held {
+		require.NoError(t, p.Put(obj))
+	}
+}
+
+func TestGetPriorityOrdersGetCallers(t *testing.T) {
+	// exhausted Get callers wait in the same queue with priority 0 in fair mode
+	t.Run("higher priority", func(t *testing.T) {
+		testGetPriorityWithGetCaller(t, true, 1, true)
+	})
+	t.Run("negative priority", func(t *testing.T) {
+		testGetPriorityWithGetCaller(t, true, -1, false)
+	})
+	// otherwise they block in the ring buffer, served after every queued caller
+	t.Run("negative priority without fair mode", func(t *testing.T) {
+		testGetPriorityWithGetCaller(t, false, -1, true)
+	})
+}
+
+// testGetPriorityWithGetCaller blocks a Get caller and then queues a GetPriority caller, and checks which one
+// the first returned object is handed to.
+func testGetPriorityWithGetCaller(t *testing.T, fair bool, priority int, priorityFirst bool) {
+	p, objects := createExhaustedPool(t, 2, func(b pool.PoolConfigBuilder[*TestObject]) pool.PoolConfigBuilder[*TestObject] {
+		return b.SetFairWaiting(fair)
+	})
+	defer func() {
+		require.NoError(t, p.Close())
+	}()
+
+	getDone := make(chan *TestObject, 1)
+	go func() {
+		obj, err := p.Get()
+		assert.NoError(t, err)
+		getDone <- obj
+	}()
+
+	queued := 1
+	if fair {
+		waitForWaiters(t, p, 1)
+		queued++
+	} else {
+		require.Eventually(t, func() bool {
+			return p.GetBlockedReaders() == 1
+		}, time.Second, time.Millisecond)
+	}
+
+	priorityDone := make(chan *TestObject, 1)
+	go func() {
+		obj, err := p.GetPriority(context.Background(), priority)
+		assert.NoError(t, err)
+		priorityDone <- obj
+	}()
+	waitForWaiters(t, p, queued)
+
+	first, second := getDone, priorityDone
+	if priorityFirst {
+		first, second = priorityDone, getDone
+	}
+
+	var held []*TestObject
+	for i, done := range []chan *TestObject{first, second} {
+		require.NoError(t, p.Put(objects[i]))
+		select {
+		case obj := <-done:
+			assert.NotNil(t, obj)
+			held = append(held, obj)
+		case <-time.After(time.Second):
+			t.Fatalf("waiter %d was not served in priority order", i)
+		}
+	}
+
+	for _, obj := range held {
+		require.NoError(t, p.Put(obj))
+	}
+}
+
+func TestGetPriorityNonBlocking(t *testing.T) {
+	p, objects := createExhaustedPool(t, 2, func(b pool.PoolConfigBuilder[*TestObject]) pool.PoolConfigBuilder[*TestObject] {
+		return b.SetRingBufferBlocking(false)
+	})
+	defer func() {
+		require.NoError(t, p.Close())
+	}()
+
+	done := make(chan error, 1)
+	go func() {
+		obj, err := p.GetPriority(context.Background(), 1)
+		assert.Nil(t, obj)
+		done <- err
+	}()
+
+	select {
+	case err := <-done:
+		assert.Error(t, err)
+	case <-time.After(time.Second):
+		t.Fatal("GetPriority waited on a non-blocking pool")
+	}
+
+	for _, obj := range objects {
+		require.NoError(t, p.Put(obj))
+	}
+}
+
+func TestGetPriorityNegativeInFairMode(t *testing.T) {
+	p, objects := createExhaustedPool(t, 2, func(b pool.PoolConfigBuilder[*TestObject]) pool.PoolConfigBuilder[*TestObject] {
+		return b.SetFairWaiting(true)
+	})
+	defer func() {
+		require.NoError(t, p.Close())
+	}()
+
+	priorityDone := make(chan *TestObject, 1)
+	go func() {
+		obj, err := p.GetPriority(context.Background(), -1)
+		assert.NoError(t, err)
+		priorityDone <- obj
+	}()
+	waitForWaiters(t, p, 1)
+
+	getDone := make(chan *TestObject, 1)
+	go func() {
+		obj, err := p.Get()
+		assert.NoError(t, err)
+		getDone <- obj
+	}()
+	waitForWaiters(t, p, 2)
+
+	var held []*TestObject
+	require.NoError(t, p.Put(objects[0]))
+	select {
+	case obj := <-getDone:
+		held = append(held, obj)
+	case <-priorityDone:
+		t.Fatal("negative priority waiter was served ahead of a queued Get caller")
+	case <-time.After(time.Second):
+		t.Fatal("Get caller was not served")
+	}
+
+	require.NoError(t, p.Put(objects[1]))
+	select {
+	case obj := <-priorityDone:
+		held = append(held, obj)
+	case <-time.After(time.Second):
+		t.Fatal("negative priority waiter was not served")
+	}
+
+	for _, obj := range held {
+		require.NoError(t, p.Put(obj))
+	}
+}
+
+func TestGetPriorityContextCancel(t *testing.T) {
+	p, objects := createExhaustedPool(t, 2, func(b pool.PoolConfigBuilder[*TestObject]) pool.PoolConfigBuilder[*TestObject] {
+		return b
+	})
+	defer func() {
+		require.NoError(t, p.Close())
+	}()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	obj, err := p.GetPriority(ctx, 1)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Nil(t, obj)
+	assert.Equal(t, 0, p.GetPoolStatsSnapshot().Waiters)
+
+	for _, obj := range objects {
+		require.NoError(t, p.Put(obj))
+	}
+}
@@ -0,0 +1,108 @@
+package pool
+
+import (
+	"context"
+	"runtime/pprof"
+	"runtime/trace"
+)
+
+// poolOp identifies a slow operation of the pool in execution traces.
+type poolOp int
+
+const (
+	opRefill poolOp = iota
+	opGrow
+	opUpdateCapacity
+	opAllocate
+	opShrink
+	opRefillWait
+	opSlowPathGet
+	numPoolOps
+
+	// noOp is the parent of operations that aren't nested in another one.
+	noOp poolOp = -1
+)
+
+var poolOpNames = [numPoolOps]string{
+	opRefill:         "refill",
+	opGrow:           "grow",
+	opUpdateCapacity: "update_capacity",
+	opAllocate:       "allocate",
+	opShrink:         "shrink",
+	opRefillWait:     "refill_wait",
+	opSlowPathGet:    "slow_path_get",
+}
+
+// String returns the name of the operation.
+func (op poolOp) String() string {
+	return poolOpNames[op]
+}
+
+// regionType is the name of the trace region and task of the operation.
+func (op poolOp) regionType() string {
+	return "poolx." + poolOpNames[op]
+}
+
+// Roles of the goroutines owned by the pool, used as the poolx.op label value.
+const (
+	roleShrink       = "shrink"
+	roleReplenish    = "replenish"
+	roleStatsHistory = "stats_history"
+)
+
+// Operations of the callers whose context carries the labels, used as the poolx.op label value.
+const (
+	opGetPriority = "get_priority"
+	opGetAtomic   = "get_atomic"
+)
+
+// poolLabels returns the label set poolx.op=op, with poolx.pool=name if the pool has a name.
+func poolLabels(name, op string) pprof.LabelSet {
+	kv := []string{"poolx.op", op}
+	if name != "" {
+		kv = append(kv, "poolx.pool", name)
+	}
+	return pprof.Labels(kv...)
+}
+
+// labelGoroutine labels the calling goroutine, which must be owned by the pool, with poolx.pool and
+// poolx.op set to role when profiler labels are enabled. Goroutines of the callers are never relabeled,
+// only GetPriority and GetAtomic add labels to the context they are given, see SetProfilerLabels.
+func (p *Pool[T]) labelGoroutine(role string) {
+	if !p.config.profilerLabels {
+		return
+	}
+
+	pprof.SetGoroutineLabels(pprof.WithLabels(context.Background(), poolLabels(p.config.name, role)))
+}
+
+// opSpan is a slow operation in progress, started by startOp.
+type opSpan struct {
+	ctx    context.Context
+	region *trace.Region
+	task   *trace.Task
+}
+
+// startOp marks the start of a slow operation while tracing is enabled: a trace region, and a trace task
+// for operations that aren't nested in another one. The operations often run on the goroutine of a caller,
+// so they leave its pprof labels alone.
+func (p *Pool[T]) startOp(op, parent poolOp) opSpan {
+	var span opSpan
+
+	ctx := context.Background()
+	if parent == noOp && trace.IsEnabled() {
+		ctx, span.task = trace.NewTask(ctx, op.regionType())
+	}
+	span.ctx = ctx
+	span.region = trace.StartRegion(ctx, op.regionType())
+
+	return span
+}
+
+// end marks the end of the operation.
+func (s opSpan) end() {
+	s.region.End()
+	if s.task != nil {
+		s.task.End()
+	}
+}
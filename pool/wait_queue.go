@@ -0,0 +1,569 @@
+package pool
+
+import (
+	"container/heap"
+	"context"
+	"fmt"
+	"io"
+	"runtime/pprof"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// waiter is a Get caller parked in the wait queue until the objects it needs are handed to it.
+type waiter[T any] struct {
+	// ch receives the batch handed to the waiter, it's closed if the pool is closed.
+	ch chan []T
+
+	// need is the number of objects the waiter acquires at once.
+	need int
+
+	// batch holds the objects reserved for the waiter until it has all it needs.
+	batch []T
+
+	// queuedAt is when the waiter joined the queue, used for the wait time stats.
+	queuedAt time.Time
+
+	// priority orders the waiters, higher priorities are served first.
+	priority int
+
+	// seq breaks ties between waiters of the same priority, so they are served FIFO.
+	seq uint64
+
+	// index is the position of the waiter in the heap, -1 once it left the queue.
+	index int
+}
+
+// waiterHeap orders waiters by priority, and by arrival within the same priority.
+type waiterHeap[T any] []*waiter[T]
+
+func (h waiterHeap[T]) Len() int { return len(h) }
+
+func (h waiterHeap[T]) Less(i, j int) bool {
+	if h[i].priority != h[j].priority {
+		return h[i].priority > h[j].priority
+	}
+	return h[i].seq < h[j].seq
+}
+
+func (h waiterHeap[T]) Swap(i, j int) {
+	h[i], h[j] = h[j], h[i]
+	h[i].index = i
+	h[j].index = j
+}
+
+func (h *waiterHeap[T]) Push(x any) {
+	w := x.(*waiter[T])
+	w.index = len(*h)
+	*h = append(*h, w)
+}
+
+func (h *waiterHeap[T]) Pop() any {
+	old := *h
+	n := len(old)
+	w := old[n-1]
+	old[n-1] = nil
+	w.index = -1
+	*h = old[:n-1]
+	return w
+}
+
+// waitQueue parks blocked Get callers and hands returned objects directly to them,
+// highest priority first and longest waiter first within the same priority.
+// Objects are reserved for the waiter at the head of the queue until it has all it needs,
+// so a caller acquiring several objects at once is never starved by single object callers.
+type waitQueue[T any] struct {
+	mu      sync.Mutex
+	waiters waiterHeap[T]
+	nextSeq uint64
+	closed  bool
+
+	// length mirrors len(waiters) so the Put path can check for waiters without locking.
+	length atomic.Int64
+
+	// demand is the number of objects the queued waiters still need.
+	demand atomic.Int64
+
+	// reserved is the number of objects held in incomplete batches.
+	reserved atomic.Int64
+
+	totalWaits    atomic.Uint64
+	totalWaitTime atomic.Int64
+	maxWaitTime   atomic.Int64
+}
+
+// newWaitQueue creates an empty wait queue.
+func newWaitQueue[T any]() *waitQueue[T] {
+	return &waitQueue[T]{}
+}
+
+// len returns the number of queued waiters.
+func (q *waitQueue[T]) len() int {
+	return int(q.length.Load())
+}
+
+// pending returns the number of objects the queued waiters still need.
+func (q *waitQueue[T]) pending() int {
+	return int(q.demand.Load())
+}
+
+// enqueue adds a new waiter that needs n objects with the given priority to the queue.
+// Returns false if the queue was closed.
+func (q *waitQueue[T]) enqueue(priority, n int) (*waiter[T], bool) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if q.closed {
+		return nil, false
+	}
+
+	w := &waiter[T]{
+		ch:       make(chan []T, 1),
+		need:     n,
+		batch:    make([]T, 0, n),
+		queuedAt: time.Now(),
+		priority: priority,
+		seq:      q.nextSeq,
+	}
+	q.nextSeq++
+
+	heap.Push(&q.waiters, w)
+	q.length.Add(1)
+	q.demand.Add(int64(n))
+
+	return w, true
+}
+
+// offer reserves obj for the next waiter to be served, returns false if there are no waiters.
+// Once the waiter has all the objects it needs it leaves the queue and is returned,
+// the caller is then responsible for delivering its batch.
+func (q *waitQueue[T]) offer(obj T) (completed *waiter[T], reserved bool) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if len(q.waiters) == 0 {
+		return nil, false
+	}
+
+	w := q.waiters[0]
+	w.batch = append(w.batch, obj)
+	q.demand.Add(-1)
+
+	if len(w.batch) < w.need {
+		q.reserved.Add(1)
+		return nil, true
+	}
+
+	heap.Pop(&q.waiters)
+	q.length.Add(-1)
+	q.reserved.Add(int64(1 - w.need))
+
+	return w, true
+}
+
+// remove takes w out of the queue and returns the objects reserved for it so far.
+// Returns false if w already left the queue.
+func (q *waitQueue[T]) remove(w *waiter[T]) (reserved []T, removed bool) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if w.index < 0 {
+		return nil, false
+	}
+
+	heap.Remove(&q.waiters, w.index)
+	q.length.Add(-1)
+	q.demand.Add(int64(len(w.batch) - w.need))
+	q.reserved.Add(int64(-len(w.batch)))
+
+	reserved, w.batch = w.batch, nil
+	return reserved, true
+}
+
+// close releases every queued waiter and rejects new ones.
+// Returns the objects that were reserved for the waiters, they are never handed out.
+func (q *waitQueue[T]) close() (dropped []T) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	q.closed = true
+	for _, w := range q.waiters {
+		w.index = -1
+		dropped = append(dropped, w.batch...)
+		w.batch = nil
+		close(w.ch)
+	}
+
+	q.waiters = nil
+	q.length.Store(0)
+	q.demand.Store(0)
+	q.reserved.Store(0)
+
+	return dropped
+}
+
+// recordWait updates the wait time stats with the time w spent in the queue.
+func (q *waitQueue[T]) recordWait(w *waiter[T]) {
+	waited := int64(time.Since(w.queuedAt))
+
+	q.totalWaits.Add(1)
+	q.totalWaitTime.Add(waited)
+
+	for {
+		current := q.maxWaitTime.Load()
+		if waited <= current || q.maxWaitTime.CompareAndSwap(current, waited) {
+			return
+		}
+	}
+}
+
+// queuedGet is the exhaustion path of GetPriority, and of Get in fair mode, when the ring buffer is blocking.
+// Instead of herding on refillCond or racing in the ring buffer, the caller queues up with the given priority
+// and waits for an object to be handed to it, until ctx is done.
+func (p *Pool[T]) queuedGet(ctx context.Context, priority int) (zero T, path GetPath, err error) {
+	if p.waiters.len() == 0 {
+		if obj, found := p.tryRefillWithoutWaiting(); found {
+			return obj, GetPathRefill, nil
+		}
+	}
+
+	obj, err := p.waitForObject(ctx, priority)
+	return obj, GetPathWaitQueue, err
+}
+
+// GetAtomic retrieves n objects from the pool together, or none of them. When the pool can't
+// provide all of them, the caller is queued and objects are reserved for it as they are returned,
+// so callers acquiring several objects never deadlock holding part of what they need.
+// Waiting callers are served in the same order as GetPriority callers with priority 0.
+//
+// The wait ends when all n objects are handed to the caller, ctx is done, the ring buffer read timeout
+// expires or the pool is closed. Objects reserved for a caller that gives up are returned to the pool.
+// When the ring buffer isn't blocking, GetAtomic doesn't wait and fails if the objects can't be provided right away.
+// Returns an error if n is not positive or exceeds the hard limit of the pool.
+func (p *Pool[T]) GetAtomic(ctx context.Context, n int) (objs []T, err error) {
+	if !p.config.profilerLabels {
+		return p.getAtomicAndTrack(ctx, n)
+	}
+
+	pprof.Do(ctx, poolLabels(p.config.name, opGetAtomic), func(ctx context.Context) {
+		objs, err = p.getAtomicAndTrack(ctx, n)
+	})
+	return objs, err
+}
+
+// getAtomicAndTrack is GetAtomic without the profiler labels.
+func (p *Pool[T]) getAtomicAndTrack(ctx context.Context, n int) ([]T, error) {
+	p.mu.RLock()
+	hardLimit := p.config.hardLimit
+	p.mu.RUnlock()
+
+	if n <= 0 || n > hardLimit {
+		return nil, p.withName(fmt.Errorf("%w: %d objects requested, hard limit is %d", errInvalidBatchSize, n, hardLimit))
+	}
+
+	objs, err := p.waitForObjects(ctx, 0, n)
+	if err != nil {
+		return objs, p.withName(err)
+	}
+
+	if p.outstanding != nil {
+		for _, obj := range objs {
+			p.trackOutstanding(obj)
+		}
+	}
+
+	return objs, nil
+}
+
+// tryRefillWithoutWaiting refills L1 and gets an object from it if no other goroutine is refilling,
+// otherwise it returns immediately instead of waiting on refillCond.
+func (p *Pool[T]) tryRefillWithoutWaiting() (zero T, found bool) {
+	select {
+	case p.refillSemaphore <- struct{}{}:
+		defer p.releaseRefill()
+		return p.handleRefillScenarios()
+	default:
+		return zero, false
+	}
+}
+
+// waitForObject queues the caller with the given priority and blocks until an object is handed to it,
+// the read timeout of the ring buffer or the queue-time budget expires, ctx is done or the pool is closed.
+func (p *Pool[T]) waitForObject(ctx context.Context, priority int) (zero T, err error) {
+	objs, err := p.waitForObjects(ctx, priority, 1)
+	if err != nil {
+		return zero, err
+	}
+
+	return objs[0], nil
+}
+
+// waitForObjects is waitForObject for callers that need n objects at once.
+func (p *Pool[T]) waitForObjects(ctx context.Context, priority, n int) ([]T, error) {
+	if err := p.errIfCircuitOpen(); err != nil {
+		return nil, err
+	}
+
+	p.mu.RLock()
+	block := p.config.ringBufferConfig.Block
+	readTimeout := p.config.ringBufferConfig.RTimeout
+	queueTimeBudget := p.config.queueTimeBudget
+	p.mu.RUnlock()
+
+	// only callers that may block are admitted and timed, the others never wait
+	if block {
+		start, admitted := p.enterWait()
+		if !admitted {
+			return nil, p.overloaded()
+		}
+		defer p.leaveWait(start)
+	}
+
+	w, ok := p.waiters.enqueue(priority, n)
+	if !ok {
+		return nil, fmt.Errorf("%w: %w", errRingBufferFailed, io.EOF)
+	}
+
+	// Objects may have been stored right before we joined the queue,
+	// and if there aren't enough of them the pool may still have room to create more.
+	p.serveWaiters()
+	p.createForWaiters()
+
+	if !block {
+		select {
+		case objs, ok := <-w.ch:
+			return p.receiveHandoff(w, objs, ok)
+		default:
+			return p.abandonWait(w, errWouldBlock)
+		}
+	}
+
+	var timeout <-chan time.Time
+	if d := readTimeout; d > 0 {
+		timer := time.NewTimer(d)
+		defer timer.Stop()
+		timeout = timer.C
+	}
+
+	var budget <-chan time.Time
+	if d := queueTimeBudget; d > 0 {
+		timer := time.NewTimer(d)
+		defer timer.Stop()
+		budget = timer.C
+	}
+
+	select {
+	case objs, ok := <-w.ch:
+		return p.receiveHandoff(w, objs, ok)
+	case <-timeout:
+		return p.abandonWait(w, context.DeadlineExceeded)
+	case <-budget:
+		objs, err := p.abandonWait(w, ErrOverloaded)
+		if err != nil {
+			p.stats.overloaded.Add(1)
+		}
+		return objs, err
+	case <-ctx.Done():
+		return p.abandonWait(w, ctx.Err())
+	case <-p.ctx.Done():
+		return p.abandonWait(w, io.EOF)
+	}
+}
+
+// receiveHandoff completes a wait once the waiter's channel delivered a batch or was closed.
+func (p *Pool[T]) receiveHandoff(w *waiter[T], objs []T, ok bool) ([]T, error) {
+	p.waiters.recordWait(w)
+
+	if !ok {
+		return nil, fmt.Errorf("%w: %w", errRingBufferFailed, io.EOF)
+	}
+
+	return objs, nil
+}
+
+// abandonWait removes w from the queue and puts the objects reserved for it back into the pool.
+// If the batch was handed to w in the meantime, the batch is returned instead of the error.
+func (p *Pool[T]) abandonWait(w *waiter[T], cause error) ([]T, error) {
+	if reserved, removed := p.waiters.remove(w); removed {
+		p.waiters.recordWait(w)
+
+		for _, obj := range reserved {
+			p.storeIdle(obj)
+		}
+		if len(reserved) > 0 {
+			p.serveWaitersIfAny()
+		}
+
+		return nil, fmt.Errorf("%w: %w", errWaitFailed, cause)
+	}
+
+	objs, ok := <-w.ch
+	return p.receiveHandoff(w, objs, ok)
+}
+
+// handoff reserves obj for the next waiter, delivering the waiter's batch once it's complete.
+// Returns false if there are no waiters.
+func (p *Pool[T]) handoff(obj T) bool {
+	w, reserved := p.waiters.offer(obj)
+	if !reserved {
+		return false
+	}
+
+	if w != nil {
+		p.stats.totalGets.Add(uint64(w.need))
+		p.metrics.getsHandoff.Add(uint64(w.need))
+		w.ch <- w.batch
+	}
+
+	return true
+}
+
+// serveWaiters hands idle objects to queued waiters in priority order.
+// Objects sitting in the ring buffer are moved to L1 first.
+func (p *Pool[T]) serveWaiters() {
+	for p.serveWaitersFromL1() {
+		if !p.moveRingBufferToL1() {
+			return
+		}
+	}
+}
+
+// serveWaitersFromL1 hands the objects in L1 to queued waiters until L1 is empty or every waiter is served.
+// Returns true if the waiters still need objects.
+func (p *Pool[T]) serveWaitersFromL1() bool {
+	for p.waiters.len() > 0 {
+		obj, found := p.takeFromL1()
+		if !found {
+			return true
+		}
+
+		if !p.handoff(obj) {
+			p.storeIdle(obj)
+			return false
+		}
+	}
+
+	return false
+}
+
+// createForWaiters creates objects for the queued waiters when the idle objects can't cover their demand,
+// growing the pool if needed. It returns immediately if another goroutine is refilling.
+func (p *Pool[T]) createForWaiters() {
+	select {
+	case p.refillSemaphore <- struct{}{}:
+	default:
+		return
+	}
+
+	defer p.releaseRefill()
+
+	for {
+		demand := p.waiters.pending()
+		if demand <= 0 {
+			return
+		}
+
+		p.mu.Lock()
+		created := p.createIfSpaceAvailable(demand)
+		p.mu.Unlock()
+
+		if !created {
+			// Growing may not allocate anything by itself, in which case
+			// the objects are created on the next round.
+			p.mu.Lock()
+			capacity := p.stats.currentCapacity
+			refilled, _ := p.tryRefill(demand)
+			grew := p.stats.currentCapacity > capacity
+			p.mu.Unlock()
+
+			if !refilled && !grew {
+				return
+			}
+		}
+
+		p.serveWaitersFromL1()
+	}
+}
+
+// takeFromL1 takes an idle object from L1 without counting it as a Get.
+func (p *Pool[T]) takeFromL1() (zero T, found bool) {
+	p.mu.RLock()
+	ch := *p.cacheL1
+	p.mu.RUnlock()
+
+	select {
+	case obj, ok := <-ch:
+		return obj, ok
+	default:
+		return zero, false
+	}
+}
+
+// storeIdle puts an object that was taken but never handed out back into the pool.
+func (p *Pool[T]) storeIdle(obj T) {
+	p.mu.RLock()
+	ch := *p.cacheL1
+	p.mu.RUnlock()
+
+	if !trySendToL1(ch, obj) {
+		p.storeIdleInRingBuffer(obj)
+	}
+}
+
+// storeIdleInRingBuffer is the ring buffer fallback of storeIdle.
+func (p *Pool[T]) storeIdleInRingBuffer(obj T) {
+	p.mu.RLock()
+	pool := p.pool
+	p.mu.RUnlock()
+
+	_ = pool.Write(obj)
+}
+
+// moveRingBufferToL1 moves the objects the waiters still need from the ring buffer to L1.
+// Returns false if there was nothing to move or another goroutine is refilling,
+// in which case that goroutine serves the waiters once it's done.
+func (p *Pool[T]) moveRingBufferToL1() bool {
+	select {
+	case p.refillSemaphore <- struct{}{}:
+	default:
+		return false
+	}
+
+	defer func() {
+		<-p.refillSemaphore
+	}()
+
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	toMove := min(p.waiters.pending(), p.pool.Length(false))
+	if toMove <= 0 {
+		return false
+	}
+
+	part1, part2, err := p.getItemsToMove(toMove)
+	if err != nil {
+		return false
+	}
+
+	if err := p.moveItemsToL1(part1); err != nil {
+		return false
+	}
+
+	return p.moveItemsToL1(part2) == nil
+}
+
+// serveWaitersIfAny is the cheap check used on the Put path before serving waiters.
+func (p *Pool[T]) serveWaitersIfAny() {
+	if p.waiters.len() > 0 {
+		p.serveWaiters()
+	}
+}
+
+// releaseRefill releases the refill semaphore, waking up whoever waits for the refill to finish.
+func (p *Pool[T]) releaseRefill() {
+	p.refillCond.Broadcast()
+	<-p.refillSemaphore
+	p.serveWaitersIfAny()
+}